@@ -0,0 +1,107 @@
+// Package bipartite implements matching algorithms on bipartite graphs:
+// Hopcroft–Karp maximum matching, and the Hungarian algorithm for minimum
+// cost assignment.
+//
+// A bipartite graph has nl left vertices and nr right vertices. The results
+// are in the same shape as blossom's: match has nl+nr entries, where the
+// right vertex v is nl+v, and match[i] == j means i matches with j, or -1 if
+// no match for i.
+package bipartite
+
+// MaxMatches calculates the maximum matches of a bipartite graph by the
+// Hopcroft–Karp algorithm, in O(m sqrt(n)) time, where adj[u] are the right
+// neighbors, in [0, nr), of the left vertex u. Returns m, the max num of
+// matches, and the match.
+func MaxMatches(nl, nr int, adj [][]int) (m int, match []int) {
+	ml := make([]int, nl) // ml[u] is the right match of u, or -1.
+	mr := make([]int, nr) // mr[v] is the left match of v, or -1.
+	for u := range ml {
+		ml[u] = -1
+	}
+	for v := range mr {
+		mr[v] = -1
+	}
+	dist := make([]int, nl)
+	it := make([]int, nl)
+	q := make([]int, 0, nl)
+	var stack []int
+
+	// bfs layers the left vertices by the alternating distance from the free
+	// ones, and reports if any free right vertex is reachable.
+	bfs := func() bool {
+		q = q[:0]
+		for u := range ml {
+			dist[u] = -1
+			if ml[u] == -1 {
+				dist[u] = 0
+				q = append(q, u)
+			}
+		}
+		found := false
+		for i := 0; i < len(q); i++ {
+			u := q[i]
+			for _, v := range adj[u] {
+				if w := mr[v]; w == -1 {
+					found = true
+				} else if dist[w] == -1 {
+					dist[w] = dist[u] + 1
+					q = append(q, w)
+				}
+			}
+		}
+		return found
+	}
+
+	// dfs finds an augmenting path along the layers from the free vertex r.
+	dfs := func(r int) bool {
+		stack = append(stack[:0], r)
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			if it[u] == len(adj[u]) {
+				dist[u] = -1
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			v := adj[u][it[u]]
+			if w := mr[v]; w == -1 {
+				// Flip the path, from the bottom of the stack.
+				for _, x := range stack {
+					y := adj[x][it[x]]
+					ml[x], mr[y] = y, x
+				}
+				return true
+			} else if dist[w] == dist[u]+1 {
+				stack = append(stack, w)
+			} else {
+				it[u]++
+			}
+		}
+		return false
+	}
+
+	for bfs() {
+		for u := range it {
+			it[u] = 0
+		}
+		for u := range ml {
+			if ml[u] == -1 && dfs(u) {
+				m++
+			}
+		}
+	}
+	return m, join(ml, mr)
+}
+
+// join merges the left and the right matches into one match.
+func join(ml, mr []int) []int {
+	nl := len(ml)
+	match := make([]int, nl+len(mr))
+	for u, v := range ml {
+		match[u] = -1
+		if v != -1 {
+			match[u] = nl + v
+		}
+	}
+	copy(match[nl:], mr)
+	return match
+}
@@ -0,0 +1,163 @@
+package bipartite
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/blossom"
+)
+
+func TestMaxMatches(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		nl, nr := rand.Intn(20), rand.Intn(20)
+		adj := make([][]int, nl)
+		g := make([][]int, nl+nr)
+		if nl > 0 && nr > 0 {
+			for k := rand.Intn(3 * (nl + nr)); k > 0; k-- {
+				u, v := rand.Intn(nl), rand.Intn(nr)
+				adj[u] = append(adj[u], v)
+				g[u] = append(g[u], nl+v)
+				g[nl+v] = append(g[nl+v], u)
+			}
+		}
+		e, _ := blossom.MaxMatchesSparse(g)
+		m, match := MaxMatches(nl, nr, adj)
+		if m != e {
+			t.Fatalf("MaxMatches(%d, %d, %v): expected %d, got %d.", nl, nr, adj, e, m)
+		}
+		k := 0
+		for u, v := range match {
+			if v == -1 {
+				continue
+			}
+			k++
+			found := false
+			for _, w := range g[u] {
+				found = found || w == v
+			}
+			if match[v] != u || !found {
+				t.Fatalf("MaxMatches(%d, %d, %v): bad match %v.", nl, nr, adj, match)
+			}
+		}
+		if k != 2*m {
+			t.Fatalf("MaxMatches(%d, %d, %v): bad match %v.", nl, nr, adj, match)
+		}
+	}
+}
+
+// bruteForce returns the min cost of assigning rows to distinct columns.
+func bruteForce(cost [][]int64) int64 {
+	n, m := len(cost), len(cost[0])
+	used := make([]bool, m)
+	var dfs func(i int) int64
+	dfs = func(i int) int64 {
+		if i == n {
+			return 0
+		}
+		best := int64(math.MaxInt64)
+		for j := 0; j < m; j++ {
+			if !used[j] {
+				used[j] = true
+				if c := cost[i][j] + dfs(i+1); c < best {
+					best = c
+				}
+				used[j] = false
+			}
+		}
+		return best
+	}
+	return dfs(0)
+}
+
+func TestAssign(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		n, m := 1+rand.Intn(6), 1+rand.Intn(6)
+		cost := make([][]int64, n)
+		fcost := make([][]float64, n)
+		tcost := make([][]int64, m)
+		for j := range tcost {
+			tcost[j] = make([]int64, n)
+		}
+		for i := range cost {
+			cost[i] = make([]int64, m)
+			fcost[i] = make([]float64, m)
+			for j := range cost[i] {
+				cost[i][j] = rand.Int63n(100) - 20
+				fcost[i][j] = float64(cost[i][j]) / 4
+				tcost[j][i] = cost[i][j]
+			}
+		}
+		var e int64
+		if n <= m {
+			e = bruteForce(cost)
+		} else {
+			e = bruteForce(tcost)
+		}
+		total, match := Assign(cost)
+		if total != e {
+			t.Fatalf("Assign(%v): expected %d, got %d.", cost, e, total)
+		}
+		var g int64
+		k := 0
+		for i := 0; i < n; i++ {
+			if j := match[i]; j != -1 {
+				if match[j] != i {
+					t.Fatalf("Assign(%v): bad match %v.", cost, match)
+				}
+				g += cost[i][j-n]
+				k++
+			}
+		}
+		if g != e || k != n && k != m {
+			t.Fatalf("Assign(%v): bad match %v.", cost, match)
+		}
+		if ftotal, _ := AssignFloat(fcost); math.Abs(ftotal-float64(e)/4) > 1e-9 {
+			t.Fatalf("AssignFloat(%v): expected %v, got %v.", fcost, float64(e)/4, ftotal)
+		}
+	}
+}
+
+func TestMinVertexCover(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		nl, nr := 1+rand.Intn(15), 1+rand.Intn(15)
+		adj := make([][]int, nl)
+		for k := rand.Intn(2 * (nl + nr)); k > 0; k-- {
+			u := rand.Intn(nl)
+			adj[u] = append(adj[u], rand.Intn(nr))
+		}
+		m, match := MaxMatches(nl, nr, adj)
+		cover := MinVertexCover(nl, nr, adj, match)
+		if !VerifyCover(nl, nr, adj, match, cover) {
+			t.Fatalf("VerifyCover(%v, %v, %v): expected true.", adj, match, cover)
+		}
+		set := MaxIndependentSet(nl, nr, adj, match)
+		in := make(map[int]bool)
+		for _, u := range set {
+			in[u] = true
+		}
+		for u := range adj {
+			for _, v := range adj[u] {
+				if in[u] && in[nl+v] {
+					t.Fatalf("MaxIndependentSet(%v): %v has edge (%d, %d).", adj, set, u, nl+v)
+				}
+			}
+		}
+		if len(set) != nl+nr-m {
+			t.Fatalf("MaxIndependentSet(%v): expected size %d, got %v.", adj, nl+nr-m, set)
+		}
+		// Unmatch a pair, which is not maximum any more.
+		for u := 0; u < nl; u++ {
+			if v := match[u]; v != -1 {
+				match[u], match[v] = -1, -1
+				if VerifyCover(nl, nr, adj, match, cover) {
+					t.Fatalf("VerifyCover(%v, %v, %v): expected false.", adj, match, cover)
+				}
+				if MinVertexCover(nl, nr, adj, match) != nil || MaxIndependentSet(nl, nr, adj, match) != nil {
+					t.Fatalf("MinVertexCover(%v, %v): expected nil.", adj, match)
+				}
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package bipartite
+
+// MinVertexCover returns a minimum vertex cover of a bipartite graph given a
+// maximum match of it, by König's theorem: with Z the vertices reachable from
+// the unmatched left vertices by alternating paths, the cover is the left
+// vertices not in Z, and the right vertices in Z. Its size equals the max num
+// of matches, which proves the match maximum; see VerifyCover. It returns nil
+// if match is not a matching or not maximum, which are both checked.
+func MinVertexCover(nl, nr int, adj [][]int, match []int) []int {
+	z := reachable(nl, nr, adj, match)
+	if z == nil {
+		return nil
+	}
+	cover := []int{}
+	for u := 0; u < nl+nr; u++ {
+		if z[u] == (u >= nl) {
+			cover = append(cover, u)
+		}
+	}
+	return cover
+}
+
+// MaxIndependentSet returns a maximum independent set of a bipartite graph
+// given a maximum match of it, i.e. the complement of MinVertexCover, or nil
+// if match is not a matching or not maximum.
+func MaxIndependentSet(nl, nr int, adj [][]int, match []int) []int {
+	z := reachable(nl, nr, adj, match)
+	if z == nil {
+		return nil
+	}
+	set := []int{}
+	for u := 0; u < nl+nr; u++ {
+		if z[u] != (u >= nl) {
+			set = append(set, u)
+		}
+	}
+	return set
+}
+
+// reachable returns the vertices reachable from the unmatched left vertices
+// by alternating paths, or nil if match is not a matching, or an unmatched
+// right vertex is reachable, i.e. there is an augmenting path.
+func reachable(nl, nr int, adj [][]int, match []int) []bool {
+	if !isMatching(nl, nr, adj, match) {
+		return nil
+	}
+	z := make([]bool, nl+nr)
+	var stack []int
+	for u := 0; u < nl; u++ {
+		if match[u] == -1 {
+			z[u] = true
+			stack = append(stack, u)
+		}
+	}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range adj[u] {
+			if v += nl; !z[v] {
+				z[v] = true
+				w := match[v]
+				if w == -1 {
+					return nil
+				}
+				if !z[w] {
+					z[w] = true
+					stack = append(stack, w)
+				}
+			}
+		}
+	}
+	return z
+}
+
+// VerifyCover reports if match is a matching of a bipartite graph, and cover
+// is a vertex cover of the same size, which proves both optimal. It takes
+// O(n + m) time.
+func VerifyCover(nl, nr int, adj [][]int, match []int, cover []int) bool {
+	n := nl + nr
+	in := make([]bool, n)
+	for _, u := range cover {
+		if u < 0 || u >= n || in[u] {
+			return false
+		}
+		in[u] = true
+	}
+	if !isMatching(nl, nr, adj, match) {
+		return false
+	}
+	m := 0
+	for u := 0; u < nl; u++ {
+		if match[u] != -1 {
+			m++
+		}
+		for _, v := range adj[u] {
+			if !in[u] && !in[nl+v] {
+				return false
+			}
+		}
+	}
+	return m == len(cover)
+}
+
+// isMatching reports if match is a matching of a bipartite graph, in the form
+// returned by MaxMatches.
+func isMatching(nl, nr int, adj [][]int, match []int) bool {
+	n := nl + nr
+	if len(match) != n {
+		return false
+	}
+	for u := 0; u < nl; u++ {
+		v := match[u]
+		if v == -1 {
+			continue
+		}
+		if v < nl || v >= n || match[v] != u {
+			return false
+		}
+		found := false
+		for _, w := range adj[u] {
+			found = found || nl+w == v
+		}
+		if !found {
+			return false
+		}
+	}
+	for v := nl; v < n; v++ {
+		if u := match[v]; u != -1 && (u < 0 || u >= nl || match[u] != v) {
+			return false
+		}
+	}
+	return true
+}
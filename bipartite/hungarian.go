@@ -0,0 +1,187 @@
+package bipartite
+
+import "math"
+
+// Assign calculates the minimum cost assignment of an n x m cost matrix by the
+// Hungarian algorithm, in O(n^2 m) time: if n <= m, each row is assigned to a
+// distinct column, otherwise each column to a distinct row. Returns the total
+// cost, and the match, where row i is vertex i, and column j is vertex n+j.
+func Assign(cost [][]int64) (total int64, match []int) {
+	n := len(cost)
+	if n == 0 {
+		return 0, nil
+	}
+	m := len(cost[0])
+	if n > m {
+		t := make([][]int64, m)
+		for j := range t {
+			t[j] = make([]int64, n)
+			for i := range t[j] {
+				t[j][i] = cost[i][j]
+			}
+		}
+		total, tm := Assign(t)
+		return total, transpose(tm, m)
+	}
+	ml, mr := assignment(hungarian(cost, m), n)
+	for i, j := range ml {
+		if j != -1 {
+			total += cost[i][j]
+		}
+	}
+	return total, join(ml, mr)
+}
+
+// AssignFloat is Assign with float64 costs.
+func AssignFloat(cost [][]float64) (total float64, match []int) {
+	n := len(cost)
+	if n == 0 {
+		return 0, nil
+	}
+	m := len(cost[0])
+	if n > m {
+		t := make([][]float64, m)
+		for j := range t {
+			t[j] = make([]float64, n)
+			for i := range t[j] {
+				t[j][i] = cost[i][j]
+			}
+		}
+		total, tm := AssignFloat(t)
+		return total, transpose(tm, m)
+	}
+	ml, mr := assignment(hungarianFloat(cost, m), n)
+	for i, j := range ml {
+		if j != -1 {
+			total += cost[i][j]
+		}
+	}
+	return total, join(ml, mr)
+}
+
+// transpose returns the match of the transposed matrix, given the match tm of
+// an n x m matrix.
+func transpose(tm []int, n int) []int {
+	ml, mr := tm[n:], make([]int, n)
+	for j := range mr {
+		mr[j] = tm[j] - n
+	}
+	return join(ml, mr)
+}
+
+// assignment returns the column of each of the n rows, and the row of each
+// column, given p from hungarian.
+func assignment(p []int, n int) (ml, mr []int) {
+	m := len(p) - 1
+	ml = make([]int, n)
+	mr = make([]int, m)
+	for j := 1; j <= m; j++ {
+		mr[j-1] = p[j] - 1
+		if p[j] != 0 {
+			ml[p[j]-1] = j - 1
+		}
+	}
+	return ml, mr
+}
+
+// hungarian solves an n x m cost matrix with n <= m. Rows and columns are
+// 1-based, where column 0 is a virtual one to start the augmentation from.
+// Returns p, where p[j] is the row assigned to column j, or 0.
+func hungarian(cost [][]int64, m int) []int {
+	n := len(cost)
+	// u and v are the potentials, and way[j] is the previous column on the
+	// path.
+	u := make([]int64, n+1)
+	v := make([]int64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+	minv := make([]int64, m+1)
+	used := make([]bool, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j] = math.MaxInt64
+			used[j] = false
+		}
+		for p[j0] != 0 {
+			used[j0] = true
+			i0, j1 := p[j0], 0
+			delta := int64(math.MaxInt64)
+			for j := 1; j <= m; j++ {
+				if !used[j] {
+					if c := cost[i0-1][j-1] - u[i0] - v[j]; c < minv[j] {
+						minv[j], way[j] = c, j0
+					}
+					if minv[j] < delta {
+						delta, j1 = minv[j], j
+					}
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	return p
+}
+
+// hungarianFloat is hungarian with float64 costs.
+func hungarianFloat(cost [][]float64, m int) []int {
+	n := len(cost)
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+	minv := make([]float64, m+1)
+	used := make([]bool, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j] = math.Inf(1)
+			used[j] = false
+		}
+		for p[j0] != 0 {
+			used[j0] = true
+			i0, j1 := p[j0], 0
+			delta := math.Inf(1)
+			for j := 1; j <= m; j++ {
+				if !used[j] {
+					if c := cost[i0-1][j-1] - u[i0] - v[j]; c < minv[j] {
+						minv[j], way[j] = c, j0
+					}
+					if minv[j] < delta {
+						delta, j1 = minv[j], j
+					}
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	return p
+}
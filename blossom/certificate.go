@@ -0,0 +1,127 @@
+package blossom
+
+// Class is the class of a vertex in the Gallai–Edmonds decomposition.
+type Class int
+
+const (
+	D Class = iota // Vertices missed by some maximum matching.
+	A              // Neighbors of D, not in D.
+	C              // All the others.
+)
+
+// GallaiEdmonds returns the Gallai–Edmonds decomposition of a graph given the
+// adjacency lists and a maximum matching of it, or nil if match is not a
+// matching or not maximum, which are both checked. It grows the alternating
+// forest from all the unmatched vertices at once: the outer vertices are D,
+// and the inner ones are A, and an augmenting path found means the matching
+// is not maximum.
+func GallaiEdmonds(adj [][]int, match []int) []Class {
+	if !isMatching(adj, match) {
+		return nil
+	}
+	g := newMatcher(adj, match)
+	var roots []int
+	for u, v := range match {
+		if v == -1 {
+			roots = append(roots, u)
+		}
+	}
+	if g.grow(roots) != -1 {
+		return nil
+	}
+	cs := make([]Class, len(adj))
+	for u, l := range g.label {
+		cs[u] = []Class{C, D, A}[l]
+	}
+	return cs
+}
+
+// TutteBerge returns a Tutte–Berge witness of a maximum matching of a graph,
+// i.e. the set A of the Gallai–Edmonds decomposition, or nil if the matching
+// is not maximum. See Verify.
+func TutteBerge(adj [][]int, match []int) []int {
+	cs := GallaiEdmonds(adj, match)
+	if cs == nil {
+		return nil
+	}
+	s := []int{}
+	for u, c := range cs {
+		if c == A {
+			s = append(s, u)
+		}
+	}
+	return s
+}
+
+// Verify reports if match is a matching of a graph given the adjacency lists,
+// and the set s proves it maximum by the Tutte–Berge formula: the max num of
+// matches is at most (n + |s| - odd(G - s)) / 2, where odd(G - s) is the num
+// of odd components after removing s. It takes O(n + m) time.
+func Verify(adj [][]int, match []int, s []int) bool {
+	n := len(adj)
+	if !isMatching(adj, match) {
+		return false
+	}
+	m := 0
+	for _, v := range match {
+		if v != -1 {
+			m++
+		}
+	}
+	m /= 2
+
+	removed := make([]bool, n)
+	for _, u := range s {
+		if removed[u] {
+			return false
+		}
+		removed[u] = true
+	}
+	odd := 0
+	var stack []int
+	for r := 0; r < n; r++ {
+		if removed[r] {
+			continue
+		}
+		removed[r] = true
+		size := 0
+		for stack = append(stack[:0], r); len(stack) > 0; {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			size++
+			for _, v := range adj[u] {
+				if !removed[v] {
+					removed[v] = true
+					stack = append(stack, v)
+				}
+			}
+		}
+		odd += size % 2
+	}
+	return 2*m == n+len(s)-odd
+}
+
+// isMatching reports if match is a matching of a graph given the adjacency
+// lists.
+func isMatching(adj [][]int, match []int) bool {
+	n := len(adj)
+	if len(match) != n {
+		return false
+	}
+	for u, v := range match {
+		if v == -1 {
+			continue
+		}
+		if v < 0 || v >= n || v == u || match[v] != u {
+			return false
+		}
+		found := false
+		for _, w := range adj[u] {
+			found = found || w == v
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package blossom
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGallaiEdmonds(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		n := 1 + rand.Intn(20)
+		adj, _ := randomGraph(n, rand.Intn(2*n))
+		m, match := MaxMatchesSparse(adj)
+		cs := GallaiEdmonds(adj, match)
+		if cs == nil {
+			t.Fatalf("GallaiEdmonds(%v): got nil for a maximum matching.", adj)
+		}
+		for u := 0; u < n; u++ {
+			// u is in D iff removing u keeps the max num of matches.
+			e := C
+			if k, _ := MaxMatchesSparse(without(adj, u)); k == m {
+				e = D
+			} else {
+				for _, v := range adj[u] {
+					if k2, _ := MaxMatchesSparse(without(adj, v)); k2 == m {
+						e = A
+					}
+				}
+			}
+			if cs[u] != e {
+				t.Fatalf("GallaiEdmonds(%v): class of %d: expected %d, got %d.", adj, u, e, cs[u])
+			}
+		}
+
+		s := TutteBerge(adj, match)
+		if !Verify(adj, match, s) {
+			t.Fatalf("Verify(%v, %v, %v): expected true.", adj, match, s)
+		}
+		// Unmatch a pair, which is not maximum any more.
+		for u, v := range match {
+			if v != -1 {
+				match[u], match[v] = -1, -1
+				if Verify(adj, match, s) {
+					t.Fatalf("Verify(%v, %v, %v): expected false.", adj, match, s)
+				}
+				if GallaiEdmonds(adj, match) != nil {
+					t.Fatalf("GallaiEdmonds(%v, %v): expected nil.", adj, match)
+				}
+				// A vertex matched to one which is not matched back.
+				match[u] = v
+				if GallaiEdmonds(adj, match) != nil {
+					t.Fatalf("GallaiEdmonds(%v, %v): expected nil.", adj, match)
+				}
+				break
+			}
+		}
+	}
+}
+
+func without(adj [][]int, u int) [][]int {
+	b := make([][]int, len(adj))
+	for x := range adj {
+		for _, y := range adj[x] {
+			if x != u && y != u {
+				b[x] = append(b[x], y)
+			}
+		}
+	}
+	return b
+}
@@ -0,0 +1,258 @@
+package blossom
+
+// MaxMatchesSparse calculates the maximum matches of a graph given the
+// adjacency lists, the same as MaxMatches, but in O(n + m) memory. It starts
+// from a greedy matching.
+func MaxMatchesSparse(adj [][]int) (m int, match []int) {
+	return Augment(adj, Greedy(adj))
+}
+
+// Greedy returns a maximal matching of a graph given the adjacency lists,
+// which is a good start for Augment.
+func Greedy(adj [][]int) []int {
+	match := make([]int, len(adj))
+	for u := range match {
+		match[u] = -1
+	}
+	for u := range adj {
+		if match[u] != -1 {
+			continue
+		}
+		for _, v := range adj[u] {
+			if v != u && match[v] == -1 {
+				match[u], match[v] = v, u
+				break
+			}
+		}
+	}
+	return match
+}
+
+// Augment augments a matching of a graph given the adjacency lists to a
+// maximum one, in place, where a nil match means the empty matching. It
+// searches once from each free vertex, in O(m) each, so it takes O(f m) time
+// for f free vertices, which grows with the free vertices rather than the
+// missing matches; after edge insertions, use AugmentEdge instead. Returns m,
+// the max num of matches, and the match.
+func Augment(adj [][]int, match []int) (m int, _ []int) {
+	if match == nil {
+		match = make([]int, len(adj))
+		for u := range match {
+			match[u] = -1
+		}
+	}
+	g := newMatcher(adj, match)
+	for u := range match {
+		if match[u] != -1 {
+			m++
+		}
+	}
+	m /= 2
+	for u := range match {
+		if match[u] == -1 {
+			if end := g.search(u); end != -1 {
+				g.augment(end)
+				m++
+			}
+			g.reset()
+		}
+	}
+	return m, match
+}
+
+// AugmentEdge updates a maximum matching of a graph given the adjacency lists,
+// in place, after the edge (u, v) has been added to them, and reports if the
+// matching grew, i.e. by one. An augmenting path must use the new edge, so it
+// searches from u or v at most twice, in O(n + m) time, regardless of the free
+// vertices. Call it after each insertion, since the matching shall be maximum
+// without the new edge.
+func AugmentEdge(adj [][]int, match []int, u, v int) bool {
+	if u == v || match[u] == v {
+		return false
+	}
+	if match[u] != -1 {
+		u, v = v, u
+	}
+	g := newMatcher(adj, match)
+	if match[u] == -1 {
+		// The path ends at the free u.
+		if end := g.search(u); end != -1 {
+			g.augment(end)
+			return true
+		}
+		return false
+	}
+	// Both are matched, so the path is x ... w u v ... y with w the mate of
+	// u. Unmatch u and w, and search from w without u for x ... w, which is
+	// then augmented. The matching is then maximum without u, so there is a
+	// path from u iff the matching is not maximum.
+	w := match[u]
+	match[u], match[w] = -1, -1
+	g.push(u, 2) // Blocks u.
+	end := g.search(w)
+	if end == -1 {
+		match[u], match[w] = w, u
+		return false
+	}
+	g.augment(end)
+	g.reset()
+	if end = g.search(u); end != -1 {
+		g.augment(end)
+		return true
+	}
+	return false
+}
+
+// matcher holds the alternating forest of Edmonds' algorithm, with the
+// blossoms contracted by union-find.
+type matcher struct {
+	adj     [][]int
+	match   []int
+	father  []int
+	base    []int // The union-find parent, towards the base of the blossom.
+	label   []int // 1 for outer vertices, 2 for inner vertices, 0 otherwise.
+	stamp   []int
+	now     int
+	queue   []int
+	touched []int
+}
+
+func newMatcher(adj [][]int, match []int) *matcher {
+	n := len(adj)
+	g := &matcher{
+		adj:    adj,
+		match:  match,
+		father: make([]int, n),
+		base:   make([]int, n),
+		label:  make([]int, n),
+		stamp:  make([]int, n),
+	}
+	for u := 0; u < n; u++ {
+		g.father[u] = -1
+		g.base[u] = u
+	}
+	return g
+}
+
+func (g *matcher) find(u int) int {
+	r := u
+	for g.base[r] != r {
+		r = g.base[r]
+	}
+	for g.base[u] != r {
+		g.base[u], u = r, g.base[u]
+	}
+	return r
+}
+
+func (g *matcher) push(u, l int) {
+	if g.label[u] == 0 {
+		g.touched = append(g.touched, u)
+	}
+	g.label[u] = l
+	if l == 1 {
+		g.queue = append(g.queue, u)
+	}
+}
+
+// reset clears the forest, in time proportional to the vertices searched.
+func (g *matcher) reset() {
+	for _, u := range g.touched {
+		g.father[u] = -1
+		g.base[u] = u
+		g.label[u] = 0
+	}
+	g.touched = g.touched[:0]
+	g.queue = g.queue[:0]
+}
+
+// lca returns the base of the blossom closing with the outer edge (u, v), or
+// -1 if u and v are in different trees.
+func (g *matcher) lca(u, v int) int {
+	g.now++
+	u, v = g.find(u), g.find(v)
+	for u != -1 || v != -1 {
+		if u != -1 {
+			if g.stamp[u] == g.now {
+				return u
+			}
+			g.stamp[u] = g.now
+			if g.match[u] == -1 {
+				u = -1
+			} else {
+				u = g.find(g.father[g.match[u]])
+			}
+		}
+		u, v = v, u
+	}
+	return -1
+}
+
+// contract contracts the path from u up to the base b into the blossom, where
+// v is the outer vertex next to u.
+func (g *matcher) contract(u, v, b int) {
+	for g.find(u) != b {
+		g.father[u] = v
+		v = g.match[u]
+		if g.label[v] == 2 {
+			g.push(v, 1)
+		}
+		if g.find(u) == u {
+			g.base[u] = b
+		}
+		if g.find(v) == v {
+			g.base[v] = b
+		}
+		u = g.father[v]
+	}
+}
+
+// search grows the tree from the root r, until it finds an augmenting path and
+// returns its end, or returns -1.
+func (g *matcher) search(r int) int {
+	return g.grow([]int{r})
+}
+
+// grow grows the forest from the roots, until it finds an augmenting path and
+// returns a vertex on it, or returns -1. With more than one root, the path may
+// join two trees, then the vertex is not its end, and can't be augmented; see
+// search.
+func (g *matcher) grow(roots []int) int {
+	for _, r := range roots {
+		g.push(r, 1)
+	}
+	for len(g.queue) > 0 {
+		u := g.queue[0]
+		g.queue = g.queue[1:]
+		for _, v := range g.adj[u] {
+			if g.label[v] == 2 || g.find(u) == g.find(v) {
+				continue
+			}
+			if g.label[v] == 0 {
+				g.push(v, 2)
+				g.father[v] = u
+				if g.match[v] == -1 {
+					return v
+				}
+				g.push(g.match[v], 1)
+			} else if b := g.lca(u, v); b != -1 {
+				g.contract(u, v, b)
+				g.contract(v, u, b)
+			} else {
+				return v
+			}
+		}
+	}
+	return -1
+}
+
+// augment flips the matching along the path from end to its root.
+func (g *matcher) augment(end int) {
+	for v := end; v != -1; {
+		u := g.father[v]
+		w := g.match[u]
+		g.match[v] = u
+		g.match[u] = v
+		v = w
+	}
+}
@@ -0,0 +1,143 @@
+package blossom
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomGraph(n, m int) (adj [][]int, g [][]bool) {
+	adj = make([][]int, n)
+	g = make([][]bool, n)
+	for i := range g {
+		g[i] = make([]bool, n)
+	}
+	for k := 0; k < m; k++ {
+		u, v := rand.Intn(n), rand.Intn(n)
+		if u != v {
+			adj[u] = append(adj[u], v)
+			adj[v] = append(adj[v], u)
+			g[u][v], g[v][u] = true, true
+		}
+	}
+	return
+}
+
+func checkMatches(t *testing.T, adj [][]int, m int, match []int) {
+	k := 0
+	for u, v := range match {
+		if v == -1 {
+			continue
+		}
+		k++
+		ok := match[v] == u
+		found := false
+		for _, w := range adj[u] {
+			found = found || w == v
+		}
+		if !ok || !found {
+			t.Fatalf("Bad match %d - %d.", u, v)
+		}
+	}
+	if k != 2*m {
+		t.Fatalf("Expected %d matches, got %d.", m, k/2)
+	}
+}
+
+func TestMaxMatchesSparse(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		n := 1 + rand.Intn(30)
+		adj, g := randomGraph(n, rand.Intn(2*n))
+		e, _ := MaxMatches(g)
+		m, match := MaxMatchesSparse(adj)
+		if m != e {
+			t.Fatalf("MaxMatchesSparse(%v): expected %d, got %d.", adj, e, m)
+		}
+		checkMatches(t, adj, m, match)
+	}
+}
+
+func TestAugment(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		n := 1 + rand.Intn(30)
+		adj, g := randomGraph(n, rand.Intn(2*n))
+		e, _ := MaxMatches(g)
+		// Start from a random maximal matching.
+		match := make([]int, n)
+		for u := range match {
+			match[u] = -1
+		}
+		for _, u := range rand.Perm(n) {
+			if match[u] == -1 && len(adj[u]) > 0 {
+				if v := adj[u][rand.Intn(len(adj[u]))]; match[v] == -1 {
+					match[u], match[v] = v, u
+				}
+			}
+		}
+		m, match := Augment(adj, match)
+		if m != e {
+			t.Fatalf("Augment(%v): expected %d, got %d.", adj, e, m)
+		}
+		checkMatches(t, adj, m, match)
+
+		// Insert an edge, and update the matching.
+		u, v := rand.Intn(n), rand.Intn(n)
+		if u != v {
+			adj[u] = append(adj[u], v)
+			adj[v] = append(adj[v], u)
+			g[u][v], g[v][u] = true, true
+		}
+		e, _ = MaxMatches(g)
+		if m, match = Augment(adj, match); m != e {
+			t.Fatalf("Augment after inserting (%d, %d): expected %d, got %d.", u, v, e, m)
+		}
+		checkMatches(t, adj, m, match)
+	}
+	if m, _ := Augment([][]int{{1}, {0}}, nil); m != 1 {
+		t.Errorf("Augment from nil: expected 1, got %d.", m)
+	}
+}
+
+func TestAugmentEdge(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		n := 1 + rand.Intn(30)
+		adj := make([][]int, n)
+		g := make([][]bool, n)
+		for u := range g {
+			g[u] = make([]bool, n)
+		}
+		match := make([]int, n)
+		for u := range match {
+			match[u] = -1
+		}
+		m := 0
+		for k := 0; k < 2*n; k++ {
+			u, v := rand.Intn(n), rand.Intn(n)
+			if u != v {
+				adj[u] = append(adj[u], v)
+				adj[v] = append(adj[v], u)
+				g[u][v], g[v][u] = true, true
+			}
+			if AugmentEdge(adj, match, u, v) {
+				m++
+			}
+			if e, _ := MaxMatches(g); m != e {
+				t.Fatalf("AugmentEdge(%v, %d, %d): expected %d, got %d.", adj, u, v, e, m)
+			}
+			checkMatches(t, adj, m, match)
+		}
+	}
+}
+
+func BenchmarkMaxMatchesSparse(b *testing.B) {
+	const n = 200000
+	adj := make([][]int, n)
+	for k := 0; k < 3*n/2; k++ {
+		u, v := rand.Intn(n), rand.Intn(n)
+		adj[u] = append(adj[u], v)
+		adj[v] = append(adj[v], u)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MaxMatchesSparse(adj)
+	}
+}
@@ -0,0 +1,602 @@
+package blossom
+
+// Edge is an undirected edge (U, V) of weight W.
+type Edge struct {
+	U, V int
+	W    int64
+}
+
+// MaxWeightMatching calculates the maximum weight matching of a graph of n
+// vertices given the edges, by the weighted blossom algorithm with dual
+// variables, in O(n^3) time. Returns w, the total weight of the matching, and
+// match, match[i] == j means i matches with j, or -1 if no match for i.
+func MaxWeightMatching(n int, es []Edge) (w int64, match []int) {
+	return newWeighted(n, es, false).solve()
+}
+
+// MaxWeightPerfectMatching calculates the maximum weight perfect matching of
+// a graph of n vertices given the edges, the same as MaxWeightMatching,
+// except that ok == false if the graph has no perfect matching.
+func MaxWeightPerfectMatching(n int, es []Edge) (w int64, match []int, ok bool) {
+	w, match = newWeighted(n, es, true).solve()
+	for _, j := range match {
+		if j == -1 {
+			return 0, nil, false
+		}
+	}
+	return w, match, true
+}
+
+// weighted holds the states of the weighted blossom algorithm. Vertices are
+// [0, n), and blossoms are [n, 2n). Edge k has endpoints 2k and 2k+1, where
+// endpoint[p] is the vertex of endpoint p, and p^1 is the other endpoint.
+type weighted struct {
+	n, m    int
+	es      []Edge
+	maxCard bool
+
+	endpoint []int
+	neighbor [][]int // neighbor[v] are the remote endpoints of edges of v.
+	mate     []int   // mate[v] is the remote endpoint of the matched edge.
+
+	// label[b] is 1 for S, 2 for T, and 0 for free; labelEnd[b] is the remote
+	// endpoint of the edge through which b got its label.
+	label    []int
+	labelEnd []int
+
+	inBlossom []int // The top-level blossom of each vertex.
+	parent    []int
+	childs    [][]int
+	base      []int
+	endps     [][]int // endps[b][i] is the endpoint from childs[b][i] to the next one.
+	bestEdge  []int
+	bestEdges [][]int // The least-slack edges to neighboring S-blossoms.
+	unused    []int
+	dual      []int64 // Duals of the vertices, and of the blossoms.
+	allowed   []bool  // Whether an edge has zero slack.
+	queue     []int
+}
+
+func newWeighted(n int, es []Edge, maxCard bool) *weighted {
+	m := len(es)
+	g := &weighted{
+		n:         n,
+		m:         m,
+		es:        es,
+		maxCard:   maxCard,
+		endpoint:  make([]int, 2*m),
+		neighbor:  make([][]int, n),
+		mate:      make([]int, n),
+		label:     make([]int, 2*n),
+		labelEnd:  make([]int, 2*n),
+		inBlossom: make([]int, n),
+		parent:    make([]int, 2*n),
+		childs:    make([][]int, 2*n),
+		base:      make([]int, 2*n),
+		endps:     make([][]int, 2*n),
+		bestEdge:  make([]int, 2*n),
+		bestEdges: make([][]int, 2*n),
+		dual:      make([]int64, 2*n),
+		allowed:   make([]bool, m),
+	}
+	var maxW int64
+	for k, e := range es {
+		g.endpoint[2*k] = e.U
+		g.endpoint[2*k+1] = e.V
+		if e.U != e.V {
+			g.neighbor[e.U] = append(g.neighbor[e.U], 2*k+1)
+			g.neighbor[e.V] = append(g.neighbor[e.V], 2*k)
+		}
+		if e.W > maxW {
+			maxW = e.W
+		}
+	}
+	for v := 0; v < n; v++ {
+		g.mate[v] = -1
+		g.inBlossom[v] = v
+		g.base[v] = v
+		g.base[n+v] = -1
+		g.dual[v] = maxW
+		g.unused = append(g.unused, n+v)
+	}
+	for b := range g.parent {
+		g.parent[b] = -1
+		g.labelEnd[b] = -1
+		g.bestEdge[b] = -1
+	}
+	return g
+}
+
+// slack returns twice the slack of edge k.
+func (g *weighted) slack(k int) int64 {
+	e := g.es[k]
+	return g.dual[e.U] + g.dual[e.V] - 2*e.W
+}
+
+// leaves returns the vertices in blossom b.
+func (g *weighted) leaves(b int) []int {
+	return g.appendLeaves(nil, b)
+}
+
+func (g *weighted) appendLeaves(vs []int, b int) []int {
+	if b < g.n {
+		return append(vs, b)
+	}
+	for _, t := range g.childs[b] {
+		vs = g.appendLeaves(vs, t)
+	}
+	return vs
+}
+
+// assignLabel labels vertex w and its top-level blossom with t, reached
+// through endpoint p.
+func (g *weighted) assignLabel(w, t, p int) {
+	for {
+		b := g.inBlossom[w]
+		g.label[w], g.label[b] = t, t
+		g.labelEnd[w], g.labelEnd[b] = p, p
+		g.bestEdge[w], g.bestEdge[b] = -1, -1
+		if t == 1 {
+			g.queue = g.appendLeaves(g.queue, b)
+			return
+		}
+		// The mate of the base of a T-blossom is an S-vertex.
+		q := g.mate[g.base[b]]
+		w, t, p = g.endpoint[q], 1, q^1
+	}
+}
+
+// scanBlossom traces back from v and w to find a new blossom's base, or
+// returns -1 if an augmenting path is found.
+func (g *weighted) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := g.inBlossom[v]
+		if g.label[b]&4 != 0 {
+			base = g.base[b]
+			break
+		}
+		path = append(path, b)
+		g.label[b] = 5
+		if g.labelEnd[b] == -1 {
+			v = -1
+		} else {
+			v = g.endpoint[g.labelEnd[b]]
+			b = g.inBlossom[v]
+			v = g.endpoint[g.labelEnd[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		g.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom makes a new blossom with base, through the S-S edge k.
+func (g *weighted) addBlossom(base, k int) {
+	v, w := g.es[k].U, g.es[k].V
+	bb := g.inBlossom[base]
+	bv := g.inBlossom[v]
+	bw := g.inBlossom[w]
+	b := g.unused[len(g.unused)-1]
+	g.unused = g.unused[:len(g.unused)-1]
+	g.base[b] = base
+	g.parent[b] = -1
+	g.parent[bb] = b
+
+	var path, endps []int
+	for bv != bb {
+		g.parent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, g.labelEnd[bv])
+		v = g.endpoint[g.labelEnd[bv]]
+		bv = g.inBlossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		g.parent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, g.labelEnd[bw]^1)
+		w = g.endpoint[g.labelEnd[bw]]
+		bw = g.inBlossom[w]
+	}
+	g.childs[b] = path
+	g.endps[b] = endps
+
+	g.label[b] = 1
+	g.labelEnd[b] = g.labelEnd[bb]
+	g.dual[b] = 0
+	for _, v := range g.leaves(b) {
+		if g.label[g.inBlossom[v]] == 2 {
+			// T-vertices become S-vertices.
+			g.queue = append(g.queue, v)
+		}
+		g.inBlossom[v] = b
+	}
+
+	bestTo := make([]int, 2*g.n)
+	for i := range bestTo {
+		bestTo[i] = -1
+	}
+	for _, bv := range path {
+		var lists [][]int
+		if g.bestEdges[bv] == nil {
+			for _, v := range g.leaves(bv) {
+				ks := make([]int, len(g.neighbor[v]))
+				for i, p := range g.neighbor[v] {
+					ks[i] = p / 2
+				}
+				lists = append(lists, ks)
+			}
+		} else {
+			lists = [][]int{g.bestEdges[bv]}
+		}
+		for _, ks := range lists {
+			for _, k := range ks {
+				j := g.es[k].V
+				if g.inBlossom[j] == b {
+					j = g.es[k].U
+				}
+				bj := g.inBlossom[j]
+				if bj != b && g.label[bj] == 1 && (bestTo[bj] == -1 || g.slack(k) < g.slack(bestTo[bj])) {
+					bestTo[bj] = k
+				}
+			}
+		}
+		g.bestEdges[bv] = nil
+		g.bestEdge[bv] = -1
+	}
+	g.bestEdges[b] = []int{}
+	g.bestEdge[b] = -1
+	for _, k := range bestTo {
+		if k != -1 {
+			g.bestEdges[b] = append(g.bestEdges[b], k)
+			if g.bestEdge[b] == -1 || g.slack(k) < g.slack(g.bestEdge[b]) {
+				g.bestEdge[b] = k
+			}
+		}
+	}
+}
+
+func reverse(a []int) {
+	for i, j := 0, len(a)-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
+// at returns a[j], where a negative j counts from the end.
+func at(a []int, j int) int {
+	if j < 0 {
+		j += len(a)
+	}
+	return a[j]
+}
+
+// expandBlossom expands blossom b into its children, relabeling them if b is
+// a T-blossom in the middle of a stage.
+func (g *weighted) expandBlossom(b int, endStage bool) {
+	for _, s := range g.childs[b] {
+		g.parent[s] = -1
+		if s < g.n {
+			g.inBlossom[s] = s
+		} else if endStage && g.dual[s] == 0 {
+			g.expandBlossom(s, endStage)
+		} else {
+			for _, v := range g.leaves(s) {
+				g.inBlossom[v] = s
+			}
+		}
+	}
+
+	if !endStage && g.label[b] == 2 {
+		// Relabel the children on the even-length path from the entry child
+		// to the base as T, S, ..., T.
+		cs, es := g.childs[b], g.endps[b]
+		entry := g.inBlossom[g.endpoint[g.labelEnd[b]^1]]
+		j := index(cs, entry)
+		var jstep, trick int
+		if j&1 != 0 {
+			j -= len(cs)
+			jstep, trick = 1, 0
+		} else {
+			jstep, trick = -1, 1
+		}
+		p := g.labelEnd[b]
+		for j != 0 {
+			g.label[g.endpoint[p^1]] = 0
+			g.label[g.endpoint[at(es, j-trick)^trick^1]] = 0
+			g.assignLabel(g.endpoint[p^1], 2, p)
+			g.allowed[at(es, j-trick)/2] = true
+			j += jstep
+			p = at(es, j-trick) ^ trick
+			g.allowed[p/2] = true
+			j += jstep
+		}
+		bv := at(cs, j)
+		g.label[g.endpoint[p^1]], g.label[bv] = 2, 2
+		g.labelEnd[g.endpoint[p^1]], g.labelEnd[bv] = p, p
+		g.bestEdge[bv] = -1
+		j += jstep
+		// The children on the other path are free, except those reachable
+		// from outside, which become T.
+		for at(cs, j) != entry {
+			bv := at(cs, j)
+			if g.label[bv] == 1 {
+				j += jstep
+				continue
+			}
+			for _, v := range g.leaves(bv) {
+				if g.label[v] != 0 {
+					g.label[v] = 0
+					g.label[g.endpoint[g.mate[g.base[bv]]]] = 0
+					g.assignLabel(v, 2, g.labelEnd[v])
+					break
+				}
+			}
+			j += jstep
+		}
+	}
+
+	g.label[b], g.labelEnd[b] = -1, -1
+	g.childs[b], g.endps[b] = nil, nil
+	g.base[b] = -1
+	g.bestEdges[b] = nil
+	g.bestEdge[b] = -1
+	g.unused = append(g.unused, b)
+}
+
+func index(a []int, x int) int {
+	for i, y := range a {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// augmentBlossom swaps the matched and unmatched edges on the path from
+// vertex v to the base of blossom b, making v the new base.
+func (g *weighted) augmentBlossom(b, v int) {
+	t := v
+	for g.parent[t] != b {
+		t = g.parent[t]
+	}
+	if t >= g.n {
+		g.augmentBlossom(t, v)
+	}
+	cs, es := g.childs[b], g.endps[b]
+	i := index(cs, t)
+	j := i
+	var jstep, trick int
+	if i&1 != 0 {
+		j -= len(cs)
+		jstep, trick = 1, 0
+	} else {
+		jstep, trick = -1, 1
+	}
+	for j != 0 {
+		j += jstep
+		t = at(cs, j)
+		p := at(es, j-trick) ^ trick
+		if t >= g.n {
+			g.augmentBlossom(t, g.endpoint[p])
+		}
+		j += jstep
+		t = at(cs, j)
+		if t >= g.n {
+			g.augmentBlossom(t, g.endpoint[p^1])
+		}
+		g.mate[g.endpoint[p]] = p ^ 1
+		g.mate[g.endpoint[p^1]] = p
+	}
+	g.childs[b] = append(cs[i:len(cs):len(cs)], cs[:i]...)
+	g.endps[b] = append(es[i:len(es):len(es)], es[:i]...)
+	g.base[b] = g.base[g.childs[b][0]]
+}
+
+// augmentMatching augments along the path through the S-S edge k.
+func (g *weighted) augmentMatching(k int) {
+	for _, sp := range [2][2]int{{g.es[k].U, 2*k + 1}, {g.es[k].V, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := g.inBlossom[s]
+			if bs >= g.n {
+				g.augmentBlossom(bs, s)
+			}
+			g.mate[s] = p
+			if g.labelEnd[bs] == -1 {
+				break
+			}
+			t := g.endpoint[g.labelEnd[bs]]
+			bt := g.inBlossom[t]
+			s = g.endpoint[g.labelEnd[bt]]
+			j := g.endpoint[g.labelEnd[bt]^1]
+			if bt >= g.n {
+				g.augmentBlossom(bt, j)
+			}
+			g.mate[j] = g.labelEnd[bt]
+			p = g.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// stage grows the alternating forest and adjusts the duals, until it finds an
+// augmenting path, or finds the matching optimal.
+func (g *weighted) stage() bool {
+	n := g.n
+	for b := range g.label {
+		g.label[b] = 0
+		g.bestEdge[b] = -1
+		if b >= n {
+			g.bestEdges[b] = nil
+		}
+	}
+	for k := range g.allowed {
+		g.allowed[k] = false
+	}
+	g.queue = g.queue[:0]
+	for v := 0; v < n; v++ {
+		if g.mate[v] == -1 && g.label[g.inBlossom[v]] == 0 {
+			g.assignLabel(v, 1, -1)
+		}
+	}
+
+	for {
+		for len(g.queue) > 0 {
+			v := g.queue[len(g.queue)-1]
+			g.queue = g.queue[:len(g.queue)-1]
+			for _, p := range g.neighbor[v] {
+				k := p / 2
+				w := g.endpoint[p]
+				if g.inBlossom[v] == g.inBlossom[w] {
+					continue
+				}
+				var slack int64
+				if !g.allowed[k] {
+					if slack = g.slack(k); slack <= 0 {
+						g.allowed[k] = true
+					}
+				}
+				if g.allowed[k] {
+					if g.label[g.inBlossom[w]] == 0 {
+						g.assignLabel(w, 2, p^1)
+					} else if g.label[g.inBlossom[w]] == 1 {
+						if base := g.scanBlossom(v, w); base >= 0 {
+							g.addBlossom(base, k)
+						} else {
+							g.augmentMatching(k)
+							return true
+						}
+					} else if g.label[w] == 0 {
+						g.label[w] = 2
+						g.labelEnd[w] = p ^ 1
+					}
+				} else if g.label[g.inBlossom[w]] == 1 {
+					b := g.inBlossom[v]
+					if g.bestEdge[b] == -1 || slack < g.slack(g.bestEdge[b]) {
+						g.bestEdge[b] = k
+					}
+				} else if g.label[w] == 0 {
+					if g.bestEdge[w] == -1 || slack < g.slack(g.bestEdge[w]) {
+						g.bestEdge[w] = k
+					}
+				}
+			}
+		}
+
+		// No more progress with tight edges, adjust the duals by delta.
+		deltaType, deltaEdge, deltaBlossom := -1, -1, -1
+		var delta int64
+		if !g.maxCard {
+			deltaType = 1
+			delta = g.dual[0]
+			for v := 1; v < n; v++ {
+				delta = min64(delta, g.dual[v])
+			}
+		}
+		for v := 0; v < n; v++ {
+			if g.label[g.inBlossom[v]] == 0 && g.bestEdge[v] != -1 {
+				if d := g.slack(g.bestEdge[v]); deltaType == -1 || d < delta {
+					delta, deltaType, deltaEdge = d, 2, g.bestEdge[v]
+				}
+			}
+		}
+		for b := 0; b < 2*n; b++ {
+			if g.parent[b] == -1 && g.label[b] == 1 && g.bestEdge[b] != -1 {
+				if d := g.slack(g.bestEdge[b]) / 2; deltaType == -1 || d < delta {
+					delta, deltaType, deltaEdge = d, 3, g.bestEdge[b]
+				}
+			}
+		}
+		for b := n; b < 2*n; b++ {
+			if g.base[b] >= 0 && g.parent[b] == -1 && g.label[b] == 2 && (deltaType == -1 || g.dual[b] < delta) {
+				delta, deltaType, deltaBlossom = g.dual[b], 4, b
+			}
+		}
+		if deltaType == -1 {
+			// Max cardinality reached, go for the optimum anyway.
+			deltaType = 1
+			delta = g.dual[0]
+			for v := 1; v < n; v++ {
+				delta = min64(delta, g.dual[v])
+			}
+			if delta < 0 {
+				delta = 0
+			}
+		}
+
+		for v := 0; v < n; v++ {
+			switch g.label[g.inBlossom[v]] {
+			case 1:
+				g.dual[v] -= delta
+			case 2:
+				g.dual[v] += delta
+			}
+		}
+		for b := n; b < 2*n; b++ {
+			if g.base[b] >= 0 && g.parent[b] == -1 {
+				switch g.label[b] {
+				case 1:
+					g.dual[b] += delta
+				case 2:
+					g.dual[b] -= delta
+				}
+			}
+		}
+
+		switch deltaType {
+		case 1:
+			return false
+		case 2:
+			g.allowed[deltaEdge] = true
+			i := g.es[deltaEdge].U
+			if g.label[g.inBlossom[i]] == 0 {
+				i = g.es[deltaEdge].V
+			}
+			g.queue = append(g.queue, i)
+		case 3:
+			g.allowed[deltaEdge] = true
+			g.queue = append(g.queue, g.es[deltaEdge].U)
+		case 4:
+			g.expandBlossom(deltaBlossom, false)
+		}
+	}
+}
+
+func (g *weighted) solve() (w int64, match []int) {
+	n := g.n
+	for t := 0; t < n; t++ {
+		if !g.stage() {
+			break
+		}
+		// Expand the S-blossoms with zero duals at the end of each stage.
+		for b := n; b < 2*n; b++ {
+			if g.parent[b] == -1 && g.base[b] >= 0 && g.label[b] == 1 && g.dual[b] == 0 {
+				g.expandBlossom(b, true)
+			}
+		}
+	}
+	match = make([]int, n)
+	for v := 0; v < n; v++ {
+		match[v] = -1
+		if p := g.mate[v]; p >= 0 {
+			match[v] = g.endpoint[p]
+			w += g.es[p/2].W
+		}
+	}
+	return w / 2, match
+}
+
+func min64(x, y int64) int64 {
+	if x < y {
+		return x
+	}
+	return y
+}
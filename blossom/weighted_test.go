@@ -0,0 +1,141 @@
+package blossom
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce returns the max weight of the matchings, and of the perfect
+// matchings, or ok == false if there are none.
+func bruteForce(n int, es []Edge) (best, bestPerfect int64, ok bool) {
+	used := make([]bool, n)
+	var dfs func(k, matched int, w int64)
+	dfs = func(k, matched int, w int64) {
+		if k == len(es) {
+			if w > best {
+				best = w
+			}
+			if matched == n && (!ok || w > bestPerfect) {
+				bestPerfect, ok = w, true
+			}
+			return
+		}
+		dfs(k+1, matched, w)
+		if e := es[k]; e.U != e.V && !used[e.U] && !used[e.V] {
+			used[e.U], used[e.V] = true, true
+			dfs(k+1, matched+2, w+e.W)
+			used[e.U], used[e.V] = false, false
+		}
+	}
+	dfs(0, 0, 0)
+	return
+}
+
+func checkMatching(t *testing.T, n int, es []Edge, w int64, match []int) {
+	// The weight of a matched pair is the max over the parallel edges.
+	ws := make(map[Edge]int64)
+	for _, e := range es {
+		if e.U > e.V {
+			e.U, e.V = e.V, e.U
+		}
+		if x, ok := ws[Edge{e.U, e.V, 0}]; !ok || e.W > x {
+			ws[Edge{e.U, e.V, 0}] = e.W
+		}
+	}
+	var g int64
+	for i, j := range match {
+		if j == -1 {
+			continue
+		}
+		x, ok := ws[Edge{i, j, 0}]
+		if match[j] != i || i == j || !ok && i < j {
+			t.Errorf("Bad match %v.", match)
+		}
+		g += x
+	}
+	if g != w {
+		t.Errorf("Match %v has weight %d, reported %d.", match, g, w)
+	}
+}
+
+func TestMaxWeightMatching(t *testing.T) {
+	w, match := MaxWeightMatching(4, []Edge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}})
+	if w != 11 || match[1] != 2 || match[0] != -1 {
+		t.Errorf("Expected 11, got %d, %v.", w, match)
+	}
+	w, match, ok := MaxWeightPerfectMatching(4, []Edge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}})
+	if !ok || w != 10 || match[0] != 1 || match[2] != 3 {
+		t.Errorf("Expected 10, got %d, %v, %v.", w, match, ok)
+	}
+	if _, _, ok := MaxWeightPerfectMatching(3, []Edge{{0, 1, 5}, {1, 2, 11}}); ok {
+		t.Errorf("Expected no perfect matching.")
+	}
+}
+
+func TestMaxWeightMatchingRandom(t *testing.T) {
+	for i := 0; i < 2000; i++ {
+		n := 1 + rand.Intn(8)
+		es := make([]Edge, rand.Intn(14))
+		for k := range es {
+			es[k] = Edge{rand.Intn(n), rand.Intn(n), rand.Int63n(20) - 4}
+		}
+		best, bestPerfect, ok := bruteForce(n, es)
+		w, match := MaxWeightMatching(n, es)
+		if w != best {
+			t.Fatalf("MaxWeightMatching(%d, %v): expected %d, got %d, %v.", n, es, best, w, match)
+		}
+		checkMatching(t, n, es, w, match)
+		w, match, ok2 := MaxWeightPerfectMatching(n, es)
+		if ok != ok2 || w != bestPerfect {
+			t.Fatalf("MaxWeightPerfectMatching(%d, %v): expected %d, %v, got %d, %v.", n, es, bestPerfect, ok, w, ok2)
+		}
+		if ok {
+			checkMatching(t, n, es, w, match)
+		}
+	}
+}
+
+// bitmaskDP returns the max weight of the matchings, by a dp over subsets of
+// the matched vertices.
+func bitmaskDP(n int, es []Edge) int64 {
+	w := make([][]int64, n)
+	for i := range w {
+		w[i] = make([]int64, n)
+	}
+	for _, e := range es {
+		if e.U != e.V && e.W > w[e.U][e.V] {
+			w[e.U][e.V], w[e.V][e.U] = e.W, e.W
+		}
+	}
+	dp := make([]int64, 1<<uint(n))
+	for s := 1; s < len(dp); s++ {
+		i := 0
+		for s&(1<<uint(i)) == 0 {
+			i++
+		}
+		r := s &^ (1 << uint(i))
+		dp[s] = dp[r]
+		for j := i + 1; j < n; j++ {
+			if r&(1<<uint(j)) != 0 && dp[r&^(1<<uint(j))]+w[i][j] > dp[s] {
+				dp[s] = dp[r&^(1<<uint(j))] + w[i][j]
+			}
+		}
+	}
+	return dp[len(dp)-1]
+}
+
+func TestMaxWeightMatchingDense(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		n := 2 + rand.Intn(13)
+		es := make([]Edge, rand.Intn(n*n))
+		for k := range es {
+			es[k] = Edge{rand.Intn(n), rand.Intn(n), rand.Int63n(1000)}
+		}
+		e := bitmaskDP(n, es)
+		if w, match := MaxWeightMatching(n, es); w != e {
+			t.Fatalf("MaxWeightMatching(%d, %v): expected %d, got %d, %v.", n, es, e, w, match)
+		} else {
+			checkMatching(t, n, es, w, match)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package centroid implements centroid decomposition of a forest.
+//
+// Every path in a tree passes through exactly one centroid-tree ancestor that
+// both its ends share at the shallowest depth, so path queries and path
+// counting reduce to O(log n) per-centroid queries.
+package centroid
+
+import "container/heap"
+
+// Decomposition is the centroid decomposition of a forest.
+type Decomposition struct {
+	adj   [][]int
+	pnt   []int
+	dep   []int
+	dists [][]int // dists[u][d] is the distance from u to its depth-d ancestor.
+}
+
+// New decomposes the forest given by the adjacency lists, e.g. lca.Tree.Adj().
+func New(adj [][]int) *Decomposition {
+	n := len(adj)
+	c := &Decomposition{
+		adj:   adj,
+		pnt:   make([]int, n),
+		dep:   make([]int, n),
+		dists: make([][]int, n),
+	}
+	for u := range c.dep {
+		c.dep[u] = -1
+	}
+
+	type task struct{ u, p int }
+	var tasks []task
+	size := make([]int, n)
+	from := make([]int, n)
+	dist := make([]int, n)
+	var q []int
+	// bfs visits the component of u, with centroids removed.
+	bfs := func(u int) []int {
+		q = append(q[:0], u)
+		from[u] = -1
+		dist[u] = 0
+		for i := 0; i < len(q); i++ {
+			x := q[i]
+			for _, y := range adj[x] {
+				if y != from[x] && c.dep[y] == -1 {
+					from[y] = x
+					dist[y] = dist[x] + 1
+					q = append(q, y)
+				}
+			}
+		}
+		return q
+	}
+
+	for r := range adj {
+		if c.dep[r] != -1 {
+			continue
+		}
+		tasks = append(tasks, task{r, -1})
+		for len(tasks) > 0 {
+			t := tasks[len(tasks)-1]
+			tasks = tasks[:len(tasks)-1]
+
+			vs := bfs(t.u)
+			for i := len(vs) - 1; i >= 0; i-- {
+				x := vs[i]
+				size[x] = 1
+				for _, y := range adj[x] {
+					if y != from[x] && c.dep[y] == -1 {
+						size[x] += size[y]
+					}
+				}
+			}
+			x := t.u
+			for moved := true; moved; {
+				moved = false
+				for _, y := range adj[x] {
+					if y != from[x] && c.dep[y] == -1 && 2*size[y] > len(vs) {
+						x, moved = y, true
+						break
+					}
+				}
+			}
+
+			for _, y := range bfs(x) {
+				c.dists[y] = append(c.dists[y], dist[y])
+			}
+			c.pnt[x] = t.p
+			c.dep[x] = len(c.dists[x]) - 1
+			for _, y := range adj[x] {
+				if c.dep[y] == -1 {
+					tasks = append(tasks, task{y, x})
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Parent returns the parent of u in the centroid tree, or -1 if u is a root.
+func (c *Decomposition) Parent(u int) int {
+	return c.pnt[u]
+}
+
+// Depth returns the depth of u in the centroid tree, which is O(log n).
+func (c *Decomposition) Depth(u int) int {
+	return c.dep[u]
+}
+
+// Dist returns the distance from u to its ancestor at depth d in the centroid
+// tree, where 0 <= d <= Depth(u).
+func (c *Decomposition) Dist(u, d int) int {
+	return c.dists[u][d]
+}
+
+// A Marker supports online marking and unmarking of nodes, and nearest marked
+// node queries, in O(log^2 n) amortized time each.
+type Marker struct {
+	c      *Decomposition
+	marked []bool
+	hs     []entries // hs[x] holds the marked nodes in the subtree of centroid x.
+}
+
+type entry struct {
+	d, u int
+}
+
+type entries []entry
+
+func (h entries) Len() int            { return len(h) }
+func (h entries) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h entries) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *entries) Push(x interface{}) { *h = append(*h, x.(entry)) }
+func (h *entries) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// NewMarker returns a Marker with no nodes marked.
+func (c *Decomposition) NewMarker() *Marker {
+	n := len(c.adj)
+	return &Marker{
+		c:      c,
+		marked: make([]bool, n),
+		hs:     make([]entries, n),
+	}
+}
+
+// Mark marks u.
+func (m *Marker) Mark(u int) {
+	if m.marked[u] {
+		return
+	}
+	m.marked[u] = true
+	for x, d := u, m.c.dep[u]; x != -1; x, d = m.c.pnt[x], d-1 {
+		heap.Push(&m.hs[x], entry{m.c.dists[u][d], u})
+	}
+}
+
+// Unmark unmarks u.
+func (m *Marker) Unmark(u int) {
+	m.marked[u] = false
+}
+
+// Nearest returns the nearest marked node to u and its distance, or -1, -1
+// if no nodes in the tree of u are marked.
+func (m *Marker) Nearest(u int) (v, d int) {
+	v, d = -1, -1
+	for x, k := u, m.c.dep[u]; x != -1; x, k = m.c.pnt[x], k-1 {
+		h := &m.hs[x]
+		for h.Len() > 0 && !m.marked[(*h)[0].u] {
+			heap.Pop(h)
+		}
+		if h.Len() > 0 {
+			if e := (*h)[0]; d == -1 || m.c.dists[u][k]+e.d < d {
+				v, d = e.u, m.c.dists[u][k]+e.d
+			}
+		}
+	}
+	return v, d
+}
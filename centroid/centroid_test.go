@@ -0,0 +1,106 @@
+package centroid
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomForest(n int) [][]int {
+	adj := make([][]int, n)
+	for u := 1; u < n; u++ {
+		if rand.Intn(10) > 0 {
+			v := rand.Intn(u)
+			adj[u] = append(adj[u], v)
+			adj[v] = append(adj[v], u)
+		}
+	}
+	return adj
+}
+
+func bfs(adj [][]int, u int) []int {
+	dist := make([]int, len(adj))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[u] = 0
+	q := []int{u}
+	for len(q) > 0 {
+		x := q[0]
+		q = q[1:]
+		for _, y := range adj[x] {
+			if dist[y] == -1 {
+				dist[y] = dist[x] + 1
+				q = append(q, y)
+			}
+		}
+	}
+	return dist
+}
+
+func TestDecomposition(t *testing.T) {
+	const n = 500
+	adj := randomForest(n)
+	c := New(adj)
+	for u := 0; u < n; u++ {
+		if (1 << uint(c.Depth(u))) > n {
+			t.Errorf("Depth(%d) = %d is too deep.", u, c.Depth(u))
+		}
+		dist := bfs(adj, u)
+		for x, d := c.Parent(u), c.Depth(u)-1; x != -1; x, d = c.Parent(x), d-1 {
+			if c.Depth(x) != d {
+				t.Errorf("Depth(%d): expected %d, got %d.", x, d, c.Depth(x))
+			}
+			if dist[x] == -1 {
+				t.Errorf("Centroid ancestor %d of %d is in another tree.", x, u)
+			}
+			if g := c.Dist(u, d); g != dist[x] {
+				t.Errorf("Dist(%d, %d): expected %d, got %d.", u, d, dist[x], g)
+			}
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	const n = 1 << 10
+	adj := make([][]int, n)
+	for u := 1; u < n; u++ {
+		adj[u] = append(adj[u], u-1)
+		adj[u-1] = append(adj[u-1], u)
+	}
+	c := New(adj)
+	for u := 0; u < n; u++ {
+		if c.Depth(u) > 10 {
+			t.Errorf("Depth(%d) = %d is too deep.", u, c.Depth(u))
+		}
+	}
+}
+
+func TestMarker(t *testing.T) {
+	const n = 300
+	adj := randomForest(n)
+	m := New(adj).NewMarker()
+	marked := make([]bool, n)
+	for i := 0; i < 2000; i++ {
+		u := rand.Intn(n)
+		switch rand.Intn(3) {
+		case 0:
+			m.Mark(u)
+			marked[u] = true
+		case 1:
+			m.Unmark(u)
+			marked[u] = false
+		default:
+			dist := bfs(adj, u)
+			e := -1
+			for v := range dist {
+				if marked[v] && dist[v] != -1 && (e == -1 || dist[v] < e) {
+					e = dist[v]
+				}
+			}
+			v, d := m.Nearest(u)
+			if d != e || (v != -1 && (!marked[v] || dist[v] != d)) {
+				t.Errorf("Nearest(%d): expected distance %d, got %d, %d.", u, e, v, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package fft
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/kelvinlau/go/number"
+)
+
+// The transforms below work in place on the arrays indexed by the subsets of
+// k bits, whose lengths shall be 2^k. The ones with suffix Mod are modulo m,
+// and the others are in int64, whose intermediate values shall fit in int64.
+// A modulus m == 0 in the unexported ones means int64.
+
+// checkBits panics if n is not a power of 2.
+func checkBits(n int) {
+	if n == 0 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("fft: length %d is not a power of 2", n))
+	}
+}
+
+// prepare checks the length of a, and reduces a modulo m.
+func prepare(a []int64, m int64) {
+	checkBits(len(a))
+	for i := range a {
+		a[i] = reduce(a[i], m)
+	}
+}
+
+// reduce returns x % m in [0, m), or x if m == 0.
+func reduce(x, m int64) int64 {
+	if m == 0 {
+		return x
+	}
+	if x %= m; x < 0 {
+		x += m
+	}
+	return x
+}
+
+// hadamard transforms a by Walsh-Hadamard, i.e. a[S] = sum((-1)^|S&T| a[T]).
+func hadamard(a []int64, m int64) {
+	prepare(a, m)
+	for h := 1; h < len(a); h *= 2 {
+		for i := 0; i < len(a); i += 2 * h {
+			for j := i; j < i+h; j++ {
+				x, y := a[j], a[j+h]
+				a[j], a[j+h] = reduce(x+y, m), reduce(x-y, m)
+			}
+		}
+	}
+}
+
+// invHadamard inverts hadamard, which is its own inverse up to a factor n.
+func invHadamard(a []int64, m int64) {
+	hadamard(a, m)
+	n := int64(len(a))
+	if m == 0 {
+		for i := range a {
+			a[i] /= n
+		}
+		return
+	}
+	x, _, g := number.ExGCD(n%m, m)
+	if g != 1 {
+		panic(fmt.Sprintf("fft: %d is not invertible modulo %d", n, m))
+	}
+	x = reduce(x, m)
+	for i := range a {
+		a[i] = number.ModularMultiply(a[i], x, m)
+	}
+}
+
+// zeta sets a[S] to the sum of a[T] over the subsets T of S if sub, or over
+// the supersets T of S otherwise, scaled by sign for the inverse.
+func zeta(a []int64, m int64, sub bool, sign int64) {
+	prepare(a, m)
+	for h := 1; h < len(a); h *= 2 {
+		for i := 0; i < len(a); i += 2 * h {
+			for j := i; j < i+h; j++ {
+				if sub {
+					a[j+h] = reduce(a[j+h]+sign*a[j], m)
+				} else {
+					a[j] = reduce(a[j]+sign*a[j+h], m)
+				}
+			}
+		}
+	}
+}
+
+// mul returns a[i] * b[i] pointwise.
+func mul(a, b []int64, m int64) []int64 {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("fft: lengths %d and %d differ", len(a), len(b)))
+	}
+	c := make([]int64, len(a))
+	for i := range a {
+		if m == 0 {
+			c[i] = a[i] * b[i]
+		} else {
+			c[i] = number.ModularMultiply(a[i], b[i], m)
+		}
+	}
+	return c
+}
+
+// convolve returns the convolution of a and b by the transform f and its
+// inverse g.
+func convolve(a, b []int64, m int64, f, g func(a []int64, m int64)) []int64 {
+	x := append([]int64(nil), a...)
+	y := append([]int64(nil), b...)
+	f(x, m)
+	f(y, m)
+	c := mul(x, y, m)
+	g(c, m)
+	return c
+}
+
+func subsetZeta(a []int64, m int64)     { zeta(a, m, true, 1) }
+func subsetMobius(a []int64, m int64)   { zeta(a, m, true, -1) }
+func supersetZeta(a []int64, m int64)   { zeta(a, m, false, 1) }
+func supersetMobius(a []int64, m int64) { zeta(a, m, false, -1) }
+
+// subsetConvolve returns c[S] = sum(a[T] * b[S-T]) over the subsets T of S,
+// in O(2^k k^2), by the ranked zeta transforms, where the terms of |T| + |U|
+// == |S| in the product of the transforms with the ranks |T| and |U| are the
+// ones of disjoint T and U.
+func subsetConvolve(a, b []int64, m int64) []int64 {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("fft: lengths %d and %d differ", len(a), len(b)))
+	}
+	n := len(a)
+	checkBits(n)
+	k := bits.TrailingZeros(uint(n))
+	rank := func(a []int64) [][]int64 {
+		r := make([][]int64, k+1)
+		for i := range r {
+			r[i] = make([]int64, n)
+		}
+		for s, x := range a {
+			r[bits.OnesCount(uint(s))][s] = reduce(x, m)
+		}
+		for i := range r {
+			subsetZeta(r[i], m)
+		}
+		return r
+	}
+	fa, fb := rank(a), rank(b)
+	c := make([]int64, n)
+	h := make([]int64, n)
+	for r := 0; r <= k; r++ {
+		for s := range h {
+			h[s] = 0
+		}
+		for i := 0; i <= r; i++ {
+			p := mul(fa[i], fb[r-i], m)
+			for s := range h {
+				h[s] = reduce(h[s]+p[s], m)
+			}
+		}
+		subsetMobius(h, m)
+		for s := range c {
+			if bits.OnesCount(uint(s)) == r {
+				c[s] = h[s]
+			}
+		}
+	}
+	return c
+}
+
+// Hadamard transforms a in place by Walsh-Hadamard, i.e. a[S] =
+// sum((-1)^|S&T| a[T]).
+func Hadamard(a []int64) { hadamard(a, 0) }
+
+// InvHadamard inverts Hadamard in place.
+func InvHadamard(a []int64) { invHadamard(a, 0) }
+
+// Zeta transforms a in place to the sums over subsets, i.e. a[S] = sum(a[T])
+// over the subsets T of S.
+func Zeta(a []int64) { subsetZeta(a, 0) }
+
+// Mobius inverts Zeta in place.
+func Mobius(a []int64) { subsetMobius(a, 0) }
+
+// SupersetZeta transforms a in place to the sums over supersets, i.e. a[S] =
+// sum(a[T]) over the supersets T of S.
+func SupersetZeta(a []int64) { supersetZeta(a, 0) }
+
+// SupersetMobius inverts SupersetZeta in place.
+func SupersetMobius(a []int64) { supersetMobius(a, 0) }
+
+// XorConvolve returns c[S] = sum(a[T] * b[U]) over T ^ U == S.
+func XorConvolve(a, b []int64) []int64 { return convolve(a, b, 0, hadamard, invHadamard) }
+
+// OrConvolve returns c[S] = sum(a[T] * b[U]) over T | U == S.
+func OrConvolve(a, b []int64) []int64 { return convolve(a, b, 0, subsetZeta, subsetMobius) }
+
+// AndConvolve returns c[S] = sum(a[T] * b[U]) over T & U == S.
+func AndConvolve(a, b []int64) []int64 { return convolve(a, b, 0, supersetZeta, supersetMobius) }
+
+// SubsetConvolve returns c[S] = sum(a[T] * b[U]) over the disjoint T and U of
+// T | U == S, in O(2^k k^2).
+func SubsetConvolve(a, b []int64) []int64 { return subsetConvolve(a, b, 0) }
+
+// HadamardMod is Hadamard modulo m.
+func HadamardMod(a []int64, m int64) { hadamard(a, m) }
+
+// InvHadamardMod is InvHadamard modulo an odd m.
+func InvHadamardMod(a []int64, m int64) { invHadamard(a, m) }
+
+// ZetaMod is Zeta modulo m.
+func ZetaMod(a []int64, m int64) { subsetZeta(a, m) }
+
+// MobiusMod is Mobius modulo m.
+func MobiusMod(a []int64, m int64) { subsetMobius(a, m) }
+
+// SupersetZetaMod is SupersetZeta modulo m.
+func SupersetZetaMod(a []int64, m int64) { supersetZeta(a, m) }
+
+// SupersetMobiusMod is SupersetMobius modulo m.
+func SupersetMobiusMod(a []int64, m int64) { supersetMobius(a, m) }
+
+// XorConvolveMod is XorConvolve modulo an odd m.
+func XorConvolveMod(a, b []int64, m int64) []int64 {
+	return convolve(a, b, m, hadamard, invHadamard)
+}
+
+// OrConvolveMod is OrConvolve modulo m.
+func OrConvolveMod(a, b []int64, m int64) []int64 {
+	return convolve(a, b, m, subsetZeta, subsetMobius)
+}
+
+// AndConvolveMod is AndConvolve modulo m.
+func AndConvolveMod(a, b []int64, m int64) []int64 {
+	return convolve(a, b, m, supersetZeta, supersetMobius)
+}
+
+// SubsetConvolveMod is SubsetConvolve modulo m.
+func SubsetConvolveMod(a, b []int64, m int64) []int64 { return subsetConvolve(a, b, m) }
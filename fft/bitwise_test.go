@@ -0,0 +1,108 @@
+package fft
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteBitwise returns c[S] = sum(a[T] * b[U]) over op(T, U) == S, modulo m
+// if m != 0.
+func bruteBitwise(a, b []int64, m int64, op func(t, u int) (int, bool)) []int64 {
+	c := make([]int64, len(a))
+	for t := range a {
+		for u := range b {
+			if s, ok := op(t, u); ok {
+				c[s] = reduce(c[s]+reduce(a[t]*b[u], m), m)
+			}
+		}
+	}
+	return c
+}
+
+func TestBitwise(t *testing.T) {
+	ops := []struct {
+		name string
+		op   func(t, u int) (int, bool)
+		f    func(a, b []int64) []int64
+		fm   func(a, b []int64, m int64) []int64
+	}{
+		{"Xor", func(t, u int) (int, bool) { return t ^ u, true }, XorConvolve, XorConvolveMod},
+		{"Or", func(t, u int) (int, bool) { return t | u, true }, OrConvolve, OrConvolveMod},
+		{"And", func(t, u int) (int, bool) { return t & u, true }, AndConvolve, AndConvolveMod},
+		{"Subset", func(t, u int) (int, bool) { return t | u, t&u == 0 }, SubsetConvolve, SubsetConvolveMod},
+	}
+	for it := 0; it < 50; it++ {
+		n := 1 << uint(rand.Intn(7))
+		a, b := randomSlice(n, -1000, 1000), randomSlice(n, -1000, 1000)
+		for _, o := range ops {
+			testEquals(o.f(a, b), bruteBitwise(a, b, 0, o.op), t)
+			testEquals(o.fm(a, b, p), bruteBitwise(a, b, p, o.op), t)
+		}
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	for it := 0; it < 50; it++ {
+		n := 1 << uint(rand.Intn(7))
+		a := randomSlice(n, -1000, 1000)
+		for _, f := range []struct {
+			name string
+			f, g func(a []int64)
+			mod  int64 // 0 if not modular.
+		}{
+			{"Hadamard", Hadamard, InvHadamard, 0},
+			{"Zeta", Zeta, Mobius, 0},
+			{"SupersetZeta", SupersetZeta, SupersetMobius, 0},
+			{"HadamardMod", func(a []int64) { HadamardMod(a, 7) }, func(a []int64) { InvHadamardMod(a, 7) }, 7},
+			{"ZetaMod", func(a []int64) { ZetaMod(a, 7) }, func(a []int64) { MobiusMod(a, 7) }, 7},
+			{"SupersetZetaMod", func(a []int64) { SupersetZetaMod(a, 7) }, func(a []int64) { SupersetMobiusMod(a, 7) }, 7},
+		} {
+			b := append([]int64(nil), a...)
+			f.f(b)
+			f.g(b)
+			e := a
+			if f.mod != 0 {
+				e = make([]int64, n)
+				for i, x := range a {
+					e[i] = reduce(x, f.mod)
+				}
+			}
+			for i := range e {
+				if b[i] != e[i] {
+					t.Errorf("%s and its inverse of %v: [%d] expected %d, got %d.", f.name, a, i, e[i], b[i])
+					break
+				}
+			}
+		}
+		z := append([]int64(nil), a...)
+		Zeta(z)
+		for s := range a {
+			var e int64
+			for u := range a {
+				if u&s == u {
+					e += a[u]
+				}
+			}
+			if z[s] != e {
+				t.Fatalf("Zeta [%d]: expected %d, got %d.", s, e, z[s])
+			}
+		}
+	}
+}
+
+func TestBitwisePanics(t *testing.T) {
+	for _, f := range []func(){
+		func() { Hadamard(make([]int64, 3)) },
+		func() { XorConvolve(make([]int64, 2), make([]int64, 4)) },
+		func() { InvHadamardMod(make([]int64, 2), 4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic.")
+				}
+			}()
+			f()
+		}()
+	}
+}
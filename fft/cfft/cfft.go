@@ -0,0 +1,242 @@
+// Package cfft implements fast fourier transform of complex128, for any
+// lengths: radix-2 for the powers of 2, and Bluestein's algorithm otherwise.
+// The transforms of real signals are computed by the half length ones.
+package cfft
+
+import (
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// roots is the twiddle table, where rt[k+j] == exp(-pi*i*j/k), shared by all
+// the radix-2 transforms of length at most len(rt).
+var roots struct {
+	mu sync.Mutex
+	rt []complex128
+}
+
+// twiddles returns the twiddle table for transforms of length n, growing it
+// if needed. Each root is computed directly for accuracy.
+func twiddles(n int) []complex128 {
+	roots.mu.Lock()
+	defer roots.mu.Unlock()
+	if len(roots.rt) < 2 {
+		roots.rt = []complex128{1, 1}
+	}
+	for k := len(roots.rt); k < n; k *= 2 {
+		rt := make([]complex128, 2*k)
+		copy(rt, roots.rt)
+		for j := 0; j < k; j++ {
+			s, c := math.Sincos(-math.Pi * float64(j) / float64(k))
+			rt[k+j] = complex(c, s)
+		}
+		roots.rt = rt
+	}
+	return roots.rt
+}
+
+// radix2 transforms a in place, whose length is a power of 2.
+func radix2(a []complex128) {
+	n := len(a)
+	rt := twiddles(n)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	for k := 1; k < n; k *= 2 {
+		w := rt[k : 2*k]
+		for i := 0; i < n; i += 2 * k {
+			x, y := a[i:i+k], a[i+k:i+2*k]
+			for j := range x {
+				z := w[j] * y[j]
+				y[j] = x[j] - z
+				x[j] += z
+			}
+		}
+	}
+}
+
+// bluestein transforms a in place, of any length n, as the convolution of
+// a[k]*w[k] and conj(w[k]), where w[k] == exp(-pi*i*k^2/n), by radix2.
+func bluestein(a []complex128) {
+	n := len(a)
+	m := 1
+	for m < 2*n-1 {
+		m *= 2
+	}
+	w := make([]complex128, n)
+	for k := range w {
+		// k^2 % 2n keeps the angles small and accurate.
+		s, c := math.Sincos(-math.Pi * float64(k*k%(2*n)) / float64(n))
+		w[k] = complex(c, s)
+	}
+	x := make([]complex128, m)
+	y := make([]complex128, m)
+	for k := range a {
+		x[k] = a[k] * w[k]
+	}
+	y[0] = 1
+	for k := 1; k < n; k++ {
+		y[k] = cmplx.Conj(w[k])
+		y[m-k] = y[k]
+	}
+	radix2(x)
+	radix2(y)
+	for i := range x {
+		x[i] *= y[i]
+	}
+	inverse(x)
+	for k := range a {
+		a[k] = x[k] * w[k]
+	}
+}
+
+// transform transforms a in place.
+func transform(a []complex128) {
+	n := len(a)
+	if n&(n-1) == 0 {
+		radix2(a)
+	} else {
+		bluestein(a)
+	}
+}
+
+// inverse inverts transform in place, by the transform of the conjugates.
+func inverse(a []complex128) {
+	for i := range a {
+		a[i] = cmplx.Conj(a[i])
+	}
+	transform(a)
+	s := 1 / float64(len(a))
+	for i := range a {
+		a[i] = complex(real(a[i])*s, -imag(a[i])*s)
+	}
+}
+
+// FFT returns the discrete fourier transform of x, i.e. y[k] = sum(x[j] *
+// exp(-2*pi*i*j*k/n)).
+func FFT(x []complex128) []complex128 {
+	a := append([]complex128(nil), x...)
+	if len(a) > 0 {
+		transform(a)
+	}
+	return a
+}
+
+// IFFT returns the inverted discrete fourier transform of y, i.e. x[j] =
+// sum(y[k] * exp(2*pi*i*j*k/n)) / n.
+func IFFT(y []complex128) []complex128 {
+	a := append([]complex128(nil), y...)
+	if len(a) > 0 {
+		inverse(a)
+	}
+	return a
+}
+
+// RFFT returns the first n/2+1 terms of the transform of a real signal x of
+// length n, as the rest are the conjugates of them. For even n, the transform
+// of length n/2 of the even and odd terms packed as complexes is split.
+func RFFT(x []float64) []complex128 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	if n%2 != 0 {
+		a := make([]complex128, n)
+		for i, v := range x {
+			a[i] = complex(v, 0)
+		}
+		transform(a)
+		return a[:n/2+1]
+	}
+	h := n / 2
+	z := make([]complex128, h)
+	for j := range z {
+		z[j] = complex(x[2*j], x[2*j+1])
+	}
+	transform(z)
+	y := make([]complex128, h+1)
+	for k := 0; k <= h; k++ {
+		zk, zc := z[k%h], cmplx.Conj(z[(h-k)%h])
+		e := (zk + zc) / 2
+		o := (zk - zc) / 2i
+		s, c := math.Sincos(-2 * math.Pi * float64(k) / float64(n))
+		y[k] = e + complex(c, s)*o
+	}
+	return y
+}
+
+// IRFFT returns the real signal of length n, given the first n/2+1 terms of
+// its transform as returned by RFFT.
+func IRFFT(y []complex128, n int) []float64 {
+	if n == 0 {
+		return nil
+	}
+	x := make([]float64, n)
+	if n%2 != 0 {
+		a := make([]complex128, n)
+		copy(a, y[:n/2+1])
+		for k := n/2 + 1; k < n; k++ {
+			a[k] = cmplx.Conj(y[n-k])
+		}
+		inverse(a)
+		for i := range x {
+			x[i] = real(a[i])
+		}
+		return x
+	}
+	// Invert the split of RFFT, then the transform of length n/2.
+	h := n / 2
+	z := make([]complex128, h)
+	for k := range z {
+		yk, yc := y[k], cmplx.Conj(y[h-k])
+		e := (yk + yc) / 2
+		s, c := math.Sincos(2 * math.Pi * float64(k) / float64(n))
+		o := (yk - yc) / 2 * complex(c, s)
+		z[k] = e + 1i*o
+	}
+	inverse(z)
+	for j, v := range z {
+		x[2*j], x[2*j+1] = real(v), imag(v)
+	}
+	return x
+}
+
+// Convolve returns the convolution of a and b, i.e. c[k] = sum(a[i] * b[j])
+// over i + j == k, of length len(a) + len(b) - 1.
+func Convolve(a, b []float64) []float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	n := len(a) + len(b) - 1
+	m := 1
+	for m < n {
+		m *= 2
+	}
+	x := make([]float64, m)
+	y := make([]float64, m)
+	copy(x, a)
+	copy(y, b)
+	p, q := RFFT(x), RFFT(y)
+	for i := range p {
+		p[i] *= q[i]
+	}
+	return IRFFT(p, m)[:n]
+}
+
+// Correlate returns the cross-correlation of a and b, i.e. c[k+len(b)-1] =
+// sum(a[i+k] * b[i]) for the lags k in (-len(b), len(a)).
+func Correlate(a, b []float64) []float64 {
+	r := make([]float64, len(b))
+	for i, v := range b {
+		r[len(b)-1-i] = v
+	}
+	return Convolve(a, r)
+}
@@ -0,0 +1,128 @@
+package cfft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+const eps = 1e-6
+
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	y := make([]complex128, n)
+	for k := range y {
+		for j, v := range x {
+			s, c := math.Sincos(-2 * math.Pi * float64(j*k%n) / float64(n))
+			y[k] += v * complex(c, s)
+		}
+	}
+	return y
+}
+
+func randomComplex(n int) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(rand.Float64()*2-1, rand.Float64()*2-1)
+	}
+	return x
+}
+
+func randomReal(n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rand.Float64()*2 - 1
+	}
+	return x
+}
+
+func testComplexEquals(t *testing.T, name string, a, e []complex128) {
+	if len(a) != len(e) {
+		t.Fatalf("%s: expected length %d, got %d.", name, len(e), len(a))
+	}
+	for i := range a {
+		if cmplx.Abs(a[i]-e[i]) > eps {
+			t.Fatalf("%s [%d]: expected %v, got %v.", name, i, e[i], a[i])
+		}
+	}
+}
+
+func testRealEquals(t *testing.T, name string, a, e []float64) {
+	if len(a) != len(e) {
+		t.Fatalf("%s: expected length %d, got %d.", name, len(e), len(a))
+	}
+	for i := range a {
+		if math.Abs(a[i]-e[i]) > eps {
+			t.Fatalf("%s [%d]: expected %f, got %f.", name, i, e[i], a[i])
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	for n := 0; n <= 70; n++ {
+		x := randomComplex(n)
+		y := FFT(x)
+		testComplexEquals(t, "FFT", y, dft(x))
+		testComplexEquals(t, "IFFT", IFFT(y), x)
+	}
+}
+
+func TestFFTLarge(t *testing.T) {
+	for _, n := range []int{1 << 16, 100003} {
+		x := randomComplex(n)
+		testComplexEquals(t, "IFFT(FFT)", IFFT(FFT(x)), x)
+	}
+}
+
+func TestRFFT(t *testing.T) {
+	for n := 0; n <= 70; n++ {
+		x := randomReal(n)
+		c := make([]complex128, n)
+		for i, v := range x {
+			c[i] = complex(v, 0)
+		}
+		y := RFFT(x)
+		e := dft(c)
+		if n > 0 {
+			e = e[:n/2+1]
+		}
+		testComplexEquals(t, "RFFT", y, e)
+		testRealEquals(t, "IRFFT", IRFFT(y, n), x)
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	for it := 0; it < 100; it++ {
+		a, b := randomReal(rand.Intn(50)+1), randomReal(rand.Intn(50)+1)
+		c := make([]float64, len(a)+len(b)-1)
+		r := make([]float64, len(a)+len(b)-1)
+		for i := range a {
+			for j := range b {
+				c[i+j] += a[i] * b[j]
+				r[i-j+len(b)-1] += a[i] * b[j]
+			}
+		}
+		testRealEquals(t, "Convolve", Convolve(a, b), c)
+		testRealEquals(t, "Correlate", Correlate(a, b), r)
+	}
+	if c := Convolve(nil, []float64{1}); c != nil {
+		t.Errorf("Convolve of empty: expected nil, got %v.", c)
+	}
+}
+
+func BenchmarkFFT(b *testing.B) {
+	x := randomComplex(1 << 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FFT(x)
+	}
+}
+
+func BenchmarkBluestein(b *testing.B) {
+	x := randomComplex(1<<16 + 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FFT(x)
+	}
+}
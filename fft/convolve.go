@@ -0,0 +1,234 @@
+package fft
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/kelvinlau/go/number"
+)
+
+// The NTT primes whose product, about 2^86, bounds the exact convolutions.
+var (
+	ntt1 = mustNTT(998244353, 3)
+	ntt2 = mustNTT(469762049, 3)
+	ntt3 = mustNTT(167772161, 3)
+)
+
+// maxLen3 is the max length of the results of the three-prime convolutions.
+const maxLen3 = 1 << 23
+
+// crt combines the residues modulo the three primes p1, p2, p3 by Garner's
+// algorithm, as x = x12 + p1*p2*k, where x12 < p1*p2 and k < p3.
+type crt struct {
+	p1, p2, p3 int64
+	p12        int64
+	i12        int64  // p1^-1 % p2.
+	i123       int64  // (p1*p2)^-1 % p3.
+	phi, plo   uint64 // p1*p2*p3 in 128 bits.
+}
+
+var crt3 = newCRT(ntt1.mod, ntt2.mod, ntt3.mod)
+
+func newCRT(p1, p2, p3 int64) *crt {
+	c := &crt{p1: p1, p2: p2, p3: p3, p12: p1 * p2}
+	c.i12 = number.ModularInvert(p1%p2, p2)
+	c.i123 = number.ModularInvert(c.p12%p3, p3)
+	c.phi, c.plo = bits.Mul64(uint64(c.p12), uint64(p3))
+	return c
+}
+
+// garner returns x12 and k such that x = x12 + p1*p2*k.
+func (c *crt) garner(r1, r2, r3 int64) (x12, k int64) {
+	x12 = r1 + c.p1*((r2-r1%c.p2+c.p2)%c.p2*c.i12%c.p2)
+	k = (r3 - x12%c.p3 + c.p3) % c.p3 * c.i123 % c.p3
+	return x12, k
+}
+
+// mod returns x % m, given p12m == p1*p2 % m.
+func (c *crt) mod(r1, r2, r3, m, p12m int64) int64 {
+	x12, k := c.garner(r1, r2, r3)
+	return (x12%m + number.ModularMultiply(p12m, k, m)) % m
+}
+
+// int64 returns x as a signed value in (-p1*p2*p3/2, p1*p2*p3/2), truncated
+// to 64 bits.
+func (c *crt) int64(r1, r2, r3 int64) int64 {
+	x12, k := c.garner(r1, r2, r3)
+	hi, lo := bits.Mul64(uint64(c.p12), uint64(k))
+	lo, carry := bits.Add64(lo, uint64(x12), 0)
+	hi += carry
+	// Compare x with p/2.
+	if hhi, hlo := c.phi>>1, c.plo>>1|c.phi<<63; hi > hhi || hi == hhi && lo > hlo {
+		lo -= c.plo
+	}
+	return int64(lo)
+}
+
+// residues returns a % p in [0, p).
+func residues(a []int64, p int64) Poly {
+	r := make(Poly, len(a))
+	for i, x := range a {
+		if r[i] = x % p; r[i] < 0 {
+			r[i] += p
+		}
+	}
+	return r
+}
+
+// transform returns the transforms of the polynomials padded to length l.
+func (t *NTT) transform(l int, ps ...[]int64) [][]uint32 {
+	ys := make([][]uint32, len(ps))
+	for i, p := range ps {
+		ys[i] = t.load(p, l)
+		t.ntt(ys[i])
+	}
+	return ys
+}
+
+// product returns the inverted transform of the pointwise product of x and y,
+// overwriting x.
+func (t *NTT) product(x, y []uint32) []uint32 {
+	t.mulTo(x, y)
+	t.intt(x)
+	return x
+}
+
+// convolveLen returns the length of the convolution of a and b, and the
+// transform length, or panics if it's too long.
+func convolveLen(a, b []int64, max int) (n, l int) {
+	n = len(a) + len(b) - 1
+	l = int(nextPowerOf2(int64(n)))
+	if l > max {
+		panic(fmt.Sprintf("fft: convolution of length %d exceeds %d", n, max))
+	}
+	return n, l
+}
+
+// schoolbookLen is the max length of the shorter input, below which the
+// schoolbook multiplication is faster than the NTT.
+const schoolbookLen = 60
+
+// Convolve returns the convolution of a and b modulo the modulus, i.e. c[k] =
+// sum(a[i] * b[j]) % mod over i + j == k, of length len(a) + len(b) - 1, which
+// shall be at most MaxLen. Short inputs are multiplied directly.
+func (t *NTT) Convolve(a, b Poly) Poly {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	n, l := convolveLen(a, b, t.maxLen)
+	if len(b) <= schoolbookLen {
+		return t.schoolbook(a, b)
+	}
+	ys := t.transform(l, a, b)
+	return t.store(t.product(ys[0], ys[1])[:n])
+}
+
+// schoolbook returns the convolution of a and b in O(len(a) * len(b)). The
+// products are reduced to x * y / R, and the sums are multiplied by R at last.
+func (t *NTT) schoolbook(a, b Poly) Poly {
+	x, y := t.load(a, len(a)), t.load(b, len(b))
+	c := make([]uint64, len(a)+len(b)-1)
+	for i, u := range x {
+		d := c[i : i+len(y)]
+		for j, v := range y {
+			d[j] += uint64(t.reduce(uint64(u) * uint64(v)))
+		}
+	}
+	r := make(Poly, len(c))
+	for k, z := range c {
+		r[k] = int64(t.toMont(uint32(z % uint64(t.p))))
+	}
+	return r
+}
+
+// Convolve returns the convolution of a and b by Default, i.e. modulo
+// 1004535809. It panics if the result is longer than Default.MaxLen().
+func Convolve(a, b []int64) []int64 {
+	return Default.Convolve(a, b)
+}
+
+// ConvolveMod returns the convolution of a and b modulo any m in [1, 2^62],
+// i.e. c[k] = sum(a[i] * b[j]) % m over i + j == k. The length of the result,
+// len(a) + len(b) - 1, shall be at most 2^23. It panics if m or the length is
+// out of range. It runs the NTTs modulo three primes, and combines the results
+// by CRT. The coefficients are split into 31 bits halves if m > 2^31.
+func ConvolveMod(a, b []int64, m int64) []int64 {
+	if m < 1 || m > 1<<62 {
+		panic(fmt.Sprintf("fft: modulus %d out of [1, 2^62]", m))
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	n, l := convolveLen(a, b, maxLen3)
+	a, b = residues(a, m), residues(b, m)
+	p12m := crt3.p12 % m
+	c := make([]int64, n)
+	if m <= 1<<31 {
+		var zs [3][]uint32
+		for i, t := range []*NTT{ntt1, ntt2, ntt3} {
+			ys := t.transform(l, a, b)
+			zs[i] = t.product(ys[0], ys[1])
+		}
+		for k := range c {
+			c[k] = crt3.mod(int64(zs[0][k]), int64(zs[1][k]), int64(zs[2][k]), m, p12m)
+		}
+		return c
+	}
+	const mask = 1<<31 - 1
+	a0, a1 := make(Poly, len(a)), make(Poly, len(a))
+	b0, b1 := make(Poly, len(b)), make(Poly, len(b))
+	for i, x := range a {
+		a0[i], a1[i] = x&mask, x>>31
+	}
+	for i, x := range b {
+		b0[i], b1[i] = x&mask, x>>31
+	}
+	// zs[i][j] is the j-th part of the product modulo the i-th prime, i.e.
+	// c = zs[0] + zs[1]*2^31 + zs[2]*2^62.
+	var zs [3][3][]uint32
+	for i, t := range []*NTT{ntt1, ntt2, ntt3} {
+		ys := t.transform(l, a0, a1, b0, b1)
+		mid := make([]uint32, l)
+		for j := range mid {
+			mid[j] = sub(t.mul(ys[0][j], ys[3][j])+t.mul(ys[1][j], ys[2][j]), t.p)
+		}
+		t.intt(mid)
+		zs[i][0] = t.product(ys[0], ys[2])
+		zs[i][1] = mid
+		zs[i][2] = t.product(ys[1], ys[3])
+	}
+	s1 := number.ModularPower(2, 31, m)
+	s2 := number.ModularPower(2, 62, m)
+	for k := range c {
+		var x [3]int64
+		for j := range x {
+			x[j] = crt3.mod(int64(zs[0][j][k]), int64(zs[1][j][k]), int64(zs[2][j][k]), m, p12m)
+		}
+		c[k] = (x[0] + number.ModularMultiply(x[1], s1, m)) % m
+		c[k] = (c[k] + number.ModularMultiply(x[2], s2, m)) % m
+	}
+	return c
+}
+
+// ConvolveInt64 returns the exact convolution of a and b, whose coefficients
+// shall fit in int64, while the intermediate products may not. The length of
+// the result, len(a) + len(b) - 1, shall be at most 2^23.
+func ConvolveInt64(a, b []int64) []int64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	n, l := convolveLen(a, b, maxLen3)
+	var zs [3][]uint32
+	for i, t := range []*NTT{ntt1, ntt2, ntt3} {
+		ys := t.transform(l, a, b)
+		zs[i] = t.product(ys[0], ys[1])
+	}
+	c := make([]int64, n)
+	for k := range c {
+		c[k] = crt3.int64(int64(zs[0][k]), int64(zs[1][k]), int64(zs[2][k]))
+	}
+	return c
+}
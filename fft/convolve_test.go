@@ -0,0 +1,174 @@
+package fft
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/number"
+)
+
+// bruteConvolve returns the convolution of a and b modulo m, or exact if m is
+// nil.
+func bruteConvolve(a, b []int64, m *big.Int) []int64 {
+	c := make([]*big.Int, len(a)+len(b)-1)
+	for k := range c {
+		c[k] = new(big.Int)
+	}
+	for i := range a {
+		for j := range b {
+			c[i+j].Add(c[i+j], new(big.Int).Mul(big.NewInt(a[i]), big.NewInt(b[j])))
+		}
+	}
+	r := make([]int64, len(c))
+	for k := range c {
+		if m != nil {
+			c[k].Mod(c[k], m)
+		}
+		r[k] = c[k].Int64()
+	}
+	return r
+}
+
+func randomSlice(n int, lo, hi int64) []int64 {
+	a := make([]int64, n)
+	for i := range a {
+		a[i] = lo + rand.Int63n(hi-lo)
+	}
+	return a
+}
+
+func TestCRT(t *testing.T) {
+	c := crt3
+	for i := 0; i < 1000; i++ {
+		r1, r2, r3 := rand.Int63n(c.p1), rand.Int63n(c.p2), rand.Int63n(c.p3)
+		x12, k := c.garner(r1, r2, r3)
+		if e := number.ModularSystem([]int64{c.p1, c.p2}, []int64{r1, r2}); x12 != e {
+			t.Fatalf("garner(%d, %d): expected %d, got %d.", r1, r2, e, x12)
+		}
+		x := new(big.Int).Mul(big.NewInt(c.p12), big.NewInt(k))
+		x.Add(x, big.NewInt(x12))
+		for _, p := range [][2]int64{{c.p1, r1}, {c.p2, r2}, {c.p3, r3}} {
+			if y := new(big.Int).Mod(x, big.NewInt(p[0])).Int64(); y != p[1] {
+				t.Fatalf("garner(%d, %d, %d): %v %% %d is %d.", r1, r2, r3, x, p[0], y)
+			}
+		}
+	}
+}
+
+func TestConvolveMod(t *testing.T) {
+	for it := 0; it < 200; it++ {
+		var m int64
+		switch it % 4 {
+		case 0:
+			m = rand.Int63n(100) + 1
+		case 1:
+			m = 998244353
+		case 2:
+			m = 1<<31 + rand.Int63n(1<<31)
+		default:
+			m = rand.Int63n(1<<62) + 1
+		}
+		a := randomSlice(rand.Intn(60)+1, -m, m)
+		b := randomSlice(rand.Intn(60)+1, 0, m)
+		e := bruteConvolve(a, b, big.NewInt(m))
+		testEquals(ConvolveMod(a, b, m), e, t)
+	}
+	if c := ConvolveMod(nil, []int64{1}, 7); len(c) != 0 {
+		t.Errorf("ConvolveMod of empty: expected [], got %v.", c)
+	}
+}
+
+func TestConvolveModLarge(t *testing.T) {
+	n := 1 << 15
+	m := int64(1<<62 - 57)
+	a := randomSlice(n, m-1000, m)
+	b := randomSlice(n, m-1000, m)
+	c := ConvolveMod(a, b, m)
+	for _, k := range []int{0, 1, n - 1, n, 2*n - 2} {
+		var s int64
+		for i := 0; i <= k; i++ {
+			if i < n && k-i < n {
+				s = (s + number.ModularMultiply(a[i], b[k-i], m)) % m
+			}
+		}
+		if c[k] != s {
+			t.Errorf("ConvolveMod [%d]: expected %d, got %d.", k, s, c[k])
+		}
+	}
+}
+
+func TestConvolveModPanics(t *testing.T) {
+	for _, m := range []int64{0, -7, 1<<62 + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ConvolveMod(%d): expected a panic.", m)
+				}
+			}()
+			ConvolveMod([]int64{1}, []int64{1}, m)
+		}()
+	}
+}
+
+func TestConvolveInt64(t *testing.T) {
+	for it := 0; it < 200; it++ {
+		n := rand.Intn(60) + 1
+		// The large results exceed the product of any two of the primes.
+		var a, b []int64
+		if it%2 == 0 {
+			a, b = randomSlice(n, -1000, 1000), randomSlice(rand.Intn(60)+1, -1000, 1000)
+		} else {
+			a, b = randomSlice(n, -1<<35, 1<<35), randomSlice(rand.Intn(60)+1, -1<<20, 1<<20)
+		}
+		testEquals(ConvolveInt64(a, b), bruteConvolve(a, b, nil), t)
+	}
+}
+
+func BenchmarkConvolveMod(b *testing.B) {
+	x := randomSlice(1<<18, 0, 1<<62)
+	y := randomSlice(1<<18, 0, 1<<62)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ConvolveMod(x, y, 1<<62)
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		a := randomSlice(rand.Intn(2*schoolbookLen)+1, -modular, modular)
+		b := randomSlice(rand.Intn(2*schoolbookLen)+1, 0, modular)
+		testEquals(Convolve(a, b), bruteConvolve(a, b, big.NewInt(modular)), t)
+	}
+	if c := Convolve([]int64{1, 2}, nil); c != nil {
+		t.Errorf("Convolve of empty: expected nil, got %v.", c)
+	}
+	n, _ := NewNTT(7681, 0)
+	testEquals(n.Convolve(Poly{1, 2}, Poly{7680, 3}), Poly{7680, 1, 6}, t)
+}
+
+func benchmarkConvolve(b *testing.B, la, lb int, f func(x, y Poly) Poly) {
+	x := randomSlice(la, 0, modular)
+	y := randomSlice(lb, 0, modular)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f(x, y)
+	}
+}
+
+// BenchmarkConvolve tunes schoolbookLen, by comparing the NTT and schoolbook
+// at the inputs of the same lengths.
+func BenchmarkConvolve(b *testing.B) {
+	ntt := func(x, y Poly) Poly {
+		l := int(nextPowerOf2(int64(len(x) + len(y) - 1)))
+		ys := Default.transform(l, x, y)
+		return Default.store(Default.product(ys[0], ys[1]))
+	}
+	for _, l := range []int{16, 32, 48, 60, 64, 128} {
+		for _, la := range []int{l, 1024} {
+			b.Run(fmt.Sprintf("ntt/%d/%d", la, l), func(b *testing.B) { benchmarkConvolve(b, la, l, ntt) })
+			b.Run(fmt.Sprintf("schoolbook/%d/%d", la, l), func(b *testing.B) { benchmarkConvolve(b, la, l, Default.schoolbook) })
+		}
+	}
+}
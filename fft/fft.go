@@ -1,13 +1,22 @@
-// Package fft implements fast fourier transform using number theory.
+// Package fft implements fast fourier transform using number theory. The
+// transforms are iterative and in place, using Montgomery arithmetic.
 package fft
 
-import "github.com/kelvinlau/go/number"
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kelvinlau/go/number"
+)
 
 const (
 	modular = 1004535809
 	omega   = 3
 )
 
+// Default is the NTT modulo 1004535809, used by FFT, IFFT and Mul.
+var Default = mustNTT(modular, omega)
+
 // Next power of 2 >= n.
 func nextPowerOf2(n int64) int64 {
 	p := int64(1)
@@ -30,51 +39,129 @@ func NewPoly(p Poly) Poly {
 	return p
 }
 
-// Mul calculates the product of p and q.
-func Mul(p, q Poly) Poly {
+// NTT is a number theoretic transform modulo a prime, whose lengths are the
+// powers of 2 dividing the modulus minus 1. It's safe for concurrent use.
+type NTT struct {
+	mod    int64
+	root   int64
+	maxLen int
+
+	// Montgomery arithmetic modulo p == mod, where R == 2^32.
+	p    uint32
+	pinv uint32 // -p^-1 % R.
+	r2   uint32 // R^2 % p.
+
+	mu sync.Mutex
+	rt []uint32 // rt[k+j] is w^j in Montgomery form, where w^(2k) == 1.
+}
+
+// NewNTT returns the NTT modulo an odd prime mod < 2^31, and a primitive root
+// of it, or the least one if root is 0.
+func NewNTT(mod, root int64) (*NTT, error) {
+	if mod < 3 || mod >= 1<<31 || !number.IsPrime(mod) {
+		return nil, fmt.Errorf("fft: modulus %d is not an odd prime < 2^31", mod)
+	}
+	if root == 0 {
+		root = number.PrimitiveRoot(mod)
+	} else if !number.IsPrimitiveRoot(mod, root) {
+		return nil, fmt.Errorf("fft: %d is not a primitive root of %d", root, mod)
+	}
+	t := &NTT{mod: mod, root: root, maxLen: 1}
+	for (mod-1)%int64(2*t.maxLen) == 0 {
+		t.maxLen *= 2
+	}
+	t.initMontgomery()
+	return t, nil
+}
+
+func mustNTT(mod, root int64) *NTT {
+	t, err := NewNTT(mod, root)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Mod returns the modulus.
+func (t *NTT) Mod() int64 {
+	return t.mod
+}
+
+// Root returns the primitive root.
+func (t *NTT) Root() int64 {
+	return t.root
+}
+
+// MaxLen returns the max transform length, the largest power of 2 dividing the
+// modulus minus 1.
+func (t *NTT) MaxLen() int {
+	return t.maxLen
+}
+
+// check returns an error if n is not a valid transform length.
+func (t *NTT) check(n int) error {
+	if n == 0 || n&(n-1) != 0 {
+		return fmt.Errorf("fft: length %d is not a power of 2", n)
+	}
+	if n > t.maxLen {
+		return fmt.Errorf("fft: length %d exceeds %d supported by modulus %d", n, t.maxLen, t.mod)
+	}
+	return nil
+}
+
+// Mul returns the pointwise product of p and q, i.e. the transform of the
+// product of the polynomials.
+func (t *NTT) Mul(p, q Poly) Poly {
 	r := make(Poly, len(p))
 	for i := range p {
-		r[i] = p[i] * q[i] % modular
+		r[i] = p[i] * q[i] % t.mod
 	}
 	return r
 }
 
-func fft(v, y Poly, r, ws, s int64) {
-	if len(y) == 1 {
-		y[0] = v[0]
-	} else {
-		m := len(y) / 2
-		fft(v[:], y[:m], r, ws*ws%r, s+s)
-		fft(v[s:], y[m:], r, ws*ws%r, s+s)
-		wsk := int64(1)
-		for k1, k2 := 0, m; k1 < m; k1, k2 = k1+1, k2+1 {
-			y[k1], y[k2] = y[k1]+wsk*y[k2], y[k1]-wsk*y[k2]
-			y[k1] = (y[k1]%r + r) % r
-			y[k2] = (y[k2]%r + r) % r
-			wsk = wsk * ws % r
-		}
+// FFT returns the fourier transform of v, whose length shall be a power of 2
+// at most MaxLen.
+func (t *NTT) FFT(v Poly) (Poly, error) {
+	if err := t.check(len(v)); err != nil {
+		return nil, err
 	}
+	a := t.load(v, len(v))
+	t.ntt(a)
+	return t.store(a), nil
 }
 
-// FFT returns the fourier transform of v.
+// IFFT returns the inverted fourier transform of v, whose length shall be a
+// power of 2 at most MaxLen.
+func (t *NTT) IFFT(v Poly) (Poly, error) {
+	if err := t.check(len(v)); err != nil {
+		return nil, err
+	}
+	a := t.load(v, len(v))
+	t.intt(a)
+	return t.store(a), nil
+}
+
+// Mul calculates the product of p and q.
+func Mul(p, q Poly) Poly {
+	return Default.Mul(p, q)
+}
+
+// FFT returns the fourier transform of v by Default. It panics if the length
+// of v is invalid.
 func FFT(v Poly) Poly {
-	y := make(Poly, len(v))
-	n := int64(len(v))
-	w := number.ModularPower(omega, (modular-1)/n, modular)
-	fft(v, y, modular, w, 1)
+	y, err := Default.FFT(v)
+	if err != nil {
+		panic(err)
+	}
 	return y
 }
 
-// IFFT returns the inverted fourier transform of v.
+// IFFT returns the inverted fourier transform of v by Default. It panics if
+// the length of v is invalid.
 func IFFT(v Poly) Poly {
-	y := make(Poly, len(v))
-	n := int64(len(v))
-	w := number.ModularPower(omega, (modular-1)/n, modular)
-	w1 := number.ModularInvert(w, modular)
-	fft(v, y, modular, w1, 1)
-	n1 := number.ModularInvert(n, modular)
-	for i := range y {
-		y[i] = y[i] * n1 % modular
+	y, err := Default.IFFT(v)
+	if err != nil {
+		panic(err)
 	}
 	return y
 }
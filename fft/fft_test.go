@@ -1,6 +1,7 @@
 package fft
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/kelvinlau/go/number"
@@ -50,6 +51,7 @@ func TestFFT(t *testing.T) {
 func testEquals(a, b Poly, t *testing.T) {
 	if len(a) != len(b) {
 		t.Errorf("Wrong length: %d", len(a))
+		return
 	}
 	for i := 0; i < len(a); i++ {
 		if a[i] != b[i] {
@@ -57,3 +59,165 @@ func testEquals(a, b Poly, t *testing.T) {
 		}
 	}
 }
+
+func TestNewNTT(t *testing.T) {
+	for _, c := range []struct {
+		mod, root int64
+		ok        bool
+		maxLen    int
+	}{
+		{998244353, 3, true, 1 << 23},
+		{469762049, 0, true, 1 << 26},
+		{167772161, 3, true, 1 << 25},
+		{1004535809, 3, true, 1 << 21},
+		{998244353, 2, false, 0},
+		{998244351, 0, false, 0},
+		{4294967291, 0, false, 0},
+		{7, 3, true, 2},
+	} {
+		n, err := NewNTT(c.mod, c.root)
+		if (err == nil) != c.ok {
+			t.Errorf("NewNTT(%d, %d): expected ok %v, got %v.", c.mod, c.root, c.ok, err)
+			continue
+		}
+		if c.ok && (n.MaxLen() != c.maxLen || n.Root() != 3) {
+			t.Errorf("NewNTT(%d, %d): expected %d 3, got %d %d.", c.mod, c.root, c.maxLen, n.MaxLen(), n.Root())
+		}
+	}
+}
+
+func TestNTTLength(t *testing.T) {
+	n, _ := NewNTT(7, 0)
+	for _, l := range []int{0, 3, 4} {
+		if _, err := n.FFT(make(Poly, l)); err == nil {
+			t.Errorf("FFT of length %d: expected an error.", l)
+		}
+		if _, err := n.IFFT(make(Poly, l)); err == nil {
+			t.Errorf("IFFT of length %d: expected an error.", l)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("FFT of length 3: expected a panic.")
+		}
+	}()
+	FFT(make(Poly, 3))
+}
+
+func TestNTTMul(t *testing.T) {
+	for _, mod := range []int64{998244353, 469762049, 7681} {
+		n, err := NewNTT(mod, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for it := 0; it < 20; it++ {
+			a := make(Poly, rand.Intn(50)+1)
+			b := make(Poly, rand.Intn(50)+1)
+			for i := range a {
+				a[i] = rand.Int63n(mod)
+			}
+			for i := range b {
+				b[i] = rand.Int63n(mod)
+			}
+			l := int(nextPowerOf2(int64(len(a) + len(b))))
+			x, y := make(Poly, l), make(Poly, l)
+			copy(x, a)
+			copy(y, b)
+			x, _ = n.FFT(x)
+			y, _ = n.FFT(y)
+			z, _ := n.IFFT(n.Mul(x, y))
+			e := make(Poly, l)
+			for i := range a {
+				for j := range b {
+					e[i+j] = (e[i+j] + a[i]*b[j]) % mod
+				}
+			}
+			testEquals(z, e, t)
+		}
+	}
+}
+
+// recursiveFFT is the former recursive transform, kept as a reference.
+func recursiveFFT(v, y Poly, r, ws, s int64) {
+	if len(y) == 1 {
+		y[0] = v[0]
+	} else {
+		m := len(y) / 2
+		recursiveFFT(v[:], y[:m], r, ws*ws%r, s+s)
+		recursiveFFT(v[s:], y[m:], r, ws*ws%r, s+s)
+		wsk := int64(1)
+		for k1, k2 := 0, m; k1 < m; k1, k2 = k1+1, k2+1 {
+			y[k1], y[k2] = y[k1]+wsk*y[k2], y[k1]-wsk*y[k2]
+			y[k1] = (y[k1]%r + r) % r
+			y[k2] = (y[k2]%r + r) % r
+			wsk = wsk * ws % r
+		}
+	}
+}
+
+func referenceFFT(t *NTT, v Poly) Poly {
+	y := make(Poly, len(v))
+	w := number.ModularPower(t.root, (t.mod-1)/int64(len(v)), t.mod)
+	recursiveFFT(v, y, t.mod, w, 1)
+	return y
+}
+
+func TestMontgomery(t *testing.T) {
+	for _, mod := range []int64{7, 998244353, 2013265921} {
+		n, _ := NewNTT(mod, 0)
+		for i := 0; i < 1000; i++ {
+			x, y := rand.Int63n(mod), rand.Int63n(mod)
+			if z := n.mul(uint32(x), uint32(y)); int64(z) != x*y%mod {
+				t.Fatalf("mul(%d, %d) %% %d: expected %d, got %d.", x, y, mod, x*y%mod, z)
+			}
+		}
+	}
+}
+
+func TestIterativeFFT(t *testing.T) {
+	for _, mod := range []int64{998244353, 469762049, 1004535809, 2013265921} {
+		n, _ := NewNTT(mod, 0)
+		for l := 1; l <= 1<<12; l *= 2 {
+			v := Poly(randomSlice(l, 0, mod))
+			y, err := n.FFT(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testEquals(y, referenceFFT(n, v), t)
+			x, _ := n.IFFT(y)
+			testEquals(x, v, t)
+		}
+	}
+}
+
+func benchmarkFFT(b *testing.B, l int, f func(v Poly)) {
+	v := Poly(randomSlice(l, 0, modular))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f(v)
+	}
+}
+
+func BenchmarkFFT16(b *testing.B) {
+	benchmarkFFT(b, 1<<16, func(v Poly) { FFT(v) })
+}
+
+func BenchmarkRecursiveFFT16(b *testing.B) {
+	benchmarkFFT(b, 1<<16, func(v Poly) { referenceFFT(Default, v) })
+}
+
+func BenchmarkFFT20(b *testing.B) {
+	benchmarkFFT(b, 1<<20, func(v Poly) { FFT(v) })
+}
+
+func BenchmarkRecursiveFFT20(b *testing.B) {
+	benchmarkFFT(b, 1<<20, func(v Poly) { referenceFFT(Default, v) })
+}
+
+func BenchmarkNTTInPlace20(b *testing.B) {
+	a := Default.load(randomSlice(1<<20, 0, modular), 1<<20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Default.ntt(a)
+	}
+}
@@ -0,0 +1,131 @@
+package fft
+
+import "github.com/kelvinlau/go/number"
+
+// initMontgomery computes the constants of the Montgomery arithmetic.
+func (t *NTT) initMontgomery() {
+	t.p = uint32(t.mod)
+	// Newton's iteration doubles the correct low bits of p^-1, starting from
+	// 3 bits, as p*p % 8 == 1.
+	inv := t.p
+	for i := 0; i < 4; i++ {
+		inv *= 2 - t.p*inv
+	}
+	t.pinv = -inv
+	r := uint64(1<<32) % uint64(t.p)
+	t.r2 = uint32(r * r % uint64(t.p))
+	t.rt = []uint32{t.toMont(1), t.toMont(1)}
+}
+
+// reduce returns x / R % p, for x < p * R.
+func (t *NTT) reduce(x uint64) uint32 {
+	m := uint32(x) * t.pinv
+	return sub(uint32((x+uint64(m)*uint64(t.p))>>32), t.p)
+}
+
+// sub returns x % p for x < 2p, without branches, which are mispredicted half
+// of the time in the butterflies.
+func sub(x, p uint32) uint32 {
+	x -= p
+	return x + p&uint32(int32(x)>>31)
+}
+
+// toMont returns x in Montgomery form, i.e. x * R % p.
+func (t *NTT) toMont(x uint32) uint32 {
+	return t.reduce(uint64(x) * uint64(t.r2))
+}
+
+// mul returns x * y % p.
+func (t *NTT) mul(x, y uint32) uint32 {
+	return t.reduce(uint64(t.reduce(uint64(x)*uint64(y))) * uint64(t.r2))
+}
+
+// roots returns the twiddle table rt for transforms of length n, growing it if
+// needed.
+func (t *NTT) roots(n int) []uint32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k := len(t.rt); k < n; k *= 2 {
+		z := t.toMont(uint32(number.ModularPower(t.root, (t.mod-1)/int64(2*k), t.mod)))
+		rt := make([]uint32, 2*k)
+		copy(rt, t.rt)
+		for i := k; i < 2*k; i++ {
+			if rt[i] = rt[i/2]; i&1 != 0 {
+				rt[i] = t.reduce(uint64(rt[i]) * uint64(z))
+			}
+		}
+		t.rt = rt
+	}
+	return t.rt
+}
+
+// load returns v % p padded to length n.
+func (t *NTT) load(v []int64, n int) []uint32 {
+	a := make([]uint32, n)
+	for i, x := range v {
+		if x %= t.mod; x < 0 {
+			x += t.mod
+		}
+		a[i] = uint32(x)
+	}
+	return a
+}
+
+// store returns a as a Poly.
+func (t *NTT) store(a []uint32) Poly {
+	v := make(Poly, len(a))
+	for i, x := range a {
+		v[i] = int64(x)
+	}
+	return v
+}
+
+// ntt transforms a in place, whose length is a valid transform length, by the
+// iterative Cooley-Tukey algorithm on the bit reversed permutation.
+func (t *NTT) ntt(a []uint32) {
+	n := len(a)
+	rt := t.roots(n)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	p := t.p
+	for k := 1; k < n; k *= 2 {
+		w := rt[k : 2*k]
+		for i := 0; i < n; i += 2 * k {
+			x, y := a[i:i+k], a[i+k:i+2*k]
+			for j := range x {
+				z := t.reduce(uint64(w[j]) * uint64(y[j]))
+				y[j] = sub(x[j]+p-z, p)
+				x[j] = sub(x[j]+z, p)
+			}
+		}
+	}
+}
+
+// intt inverts ntt in place, as the transform by w^-1 permutes the transform
+// by w.
+func (t *NTT) intt(a []uint32) {
+	n := len(a)
+	t.ntt(a)
+	for i, j := 1, n-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+	ninv := t.toMont(uint32(number.ModularInvert(int64(n), t.mod)))
+	for i := range a {
+		a[i] = t.reduce(uint64(a[i]) * uint64(ninv))
+	}
+}
+
+// mulTo sets a[i] = a[i] * b[i] % p.
+func (t *NTT) mulTo(a, b []uint32) {
+	for i := range a {
+		a[i] = t.mul(a[i], b[i])
+	}
+}
@@ -0,0 +1,144 @@
+package fft
+
+import (
+	"fmt"
+
+	"github.com/kelvinlau/go/number"
+)
+
+// leafLen is the max num of points, below which the polynomials are
+// evaluated directly by Horner's method.
+const leafLen = 32
+
+// subproduct is the subproduct tree of the points xs, where tree[k] is the
+// product of (x - xs[i]) over the range of node k, for node 1 the root of
+// [0, n), and node k's children 2k and 2k+1 of the halves.
+type subproduct struct {
+	xs   []int64
+	tree []Poly
+}
+
+func (t *NTT) newSubproduct(xs []int64) *subproduct {
+	s := &subproduct{xs: xs, tree: make([]Poly, 4*len(xs))}
+	t.build(s, 1, 0, len(xs))
+	return s
+}
+
+func (t *NTT) build(s *subproduct, k, l, r int) {
+	if r-l == 1 {
+		s.tree[k] = Poly{(t.mod - s.xs[l]) % t.mod, 1}
+		return
+	}
+	m := (l + r) / 2
+	t.build(s, 2*k, l, m)
+	t.build(s, 2*k+1, m, r)
+	s.tree[k] = t.Convolve(s.tree[2*k], s.tree[2*k+1])
+}
+
+// horner returns a(x).
+func (t *NTT) horner(a Poly, x int64) int64 {
+	var y int64
+	for i := len(a) - 1; i >= 0; i-- {
+		y = (y*x + a[i]) % t.mod
+	}
+	return y
+}
+
+// evaluate sets ys[i] = a(xs[i]) for i in [l, r), where a is reduced modulo
+// the tree of node k's parent. It returns the error of DivMod, if any.
+func (t *NTT) evaluate(s *subproduct, a Poly, k, l, r int, ys []int64) error {
+	if r-l <= leafLen {
+		for i := l; i < r; i++ {
+			ys[i] = t.horner(a, s.xs[i])
+		}
+		return nil
+	}
+	_, a, err := t.DivMod(a, s.tree[k])
+	if err != nil {
+		return err
+	}
+	m := (l + r) / 2
+	if err := t.evaluate(s, a, 2*k, l, m, ys); err != nil {
+		return err
+	}
+	return t.evaluate(s, a, 2*k+1, m, r, ys)
+}
+
+// Evaluate returns a(xs[i]) for all i, in O(n log(n)^2) for n points, by
+// reducing a modulo the subproduct tree of the points, top down.
+func (t *NTT) Evaluate(a Poly, xs []int64) ([]int64, error) {
+	n := len(xs)
+	l := len(a)
+	if l < n+1 {
+		l = n + 1
+	}
+	if err := t.seriesLen(l); err != nil {
+		return nil, err
+	}
+	a = t.pad(a, len(a))
+	xs = t.pad(xs, n)
+	ys := make([]int64, n)
+	if n == 0 {
+		return ys, nil
+	}
+	if err := t.evaluate(t.newSubproduct(xs), a, 1, 0, n, ys); err != nil {
+		return nil, err
+	}
+	return ys, nil
+}
+
+// Interpolate returns the polynomial a of degree < n such that a(xs[i]) ==
+// ys[i] for all i, in O(n log(n)^2), where the n points are distinct. By
+// Lagrange's formula, a is the sum of ys[i] / m'(xs[i]) * m / (x - xs[i]),
+// where m is the product of (x - xs[i]), combined bottom up.
+func (t *NTT) Interpolate(xs, ys []int64) (Poly, error) {
+	n := len(xs)
+	if len(ys) != n {
+		return nil, fmt.Errorf("fft: %d points with %d values", n, len(ys))
+	}
+	if err := t.seriesLen(n + 1); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return Poly{}, nil
+	}
+	xs, ys = t.pad(xs, n), t.pad(ys, n)
+	s := t.newSubproduct(xs)
+	w := make([]int64, n)
+	if err := t.evaluate(s, t.Derivative(s.tree[1]), 1, 0, n, w); err != nil {
+		return nil, err
+	}
+	for i := range w {
+		if w[i] == 0 {
+			return nil, fmt.Errorf("fft: duplicate point %d", xs[i])
+		}
+		w[i] = ys[i] * number.ModularInvert(w[i], t.mod) % t.mod
+	}
+	return t.combine(s, w, 1, 0, n), nil
+}
+
+// combine returns the sum of w[i] * m / (x - xs[i]) for i in [l, r), where m
+// is the tree of node k.
+func (t *NTT) combine(s *subproduct, w []int64, k, l, r int) Poly {
+	if r-l == 1 {
+		return Poly{w[l]}
+	}
+	m := (l + r) / 2
+	a := t.Convolve(t.combine(s, w, 2*k, l, m), s.tree[2*k+1])
+	b := t.Convolve(t.combine(s, w, 2*k+1, m, r), s.tree[2*k])
+	for i := range b {
+		a[i] = (a[i] + b[i]) % t.mod
+	}
+	return a
+}
+
+// Evaluate returns a(xs[i]) for all i, by Default.
+func Evaluate(a Poly, xs []int64) ([]int64, error) {
+	return Default.Evaluate(a, xs)
+}
+
+// Interpolate returns the polynomial a of degree < len(xs) such that
+// a(xs[i]) == ys[i] for all i, by Default.
+func Interpolate(xs, ys []int64) (Poly, error) {
+	return Default.Interpolate(xs, ys)
+}
@@ -0,0 +1,63 @@
+package fft
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	for it := 0; it < 30; it++ {
+		a := randomPoly(rand.Intn(300))
+		xs := randomSlice(rand.Intn(300), -p, p)
+		ys, err := ntt998.Evaluate(a, xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := make([]int64, len(xs))
+		for i, x := range xs {
+			e[i] = ntt998.horner(a, (x%p+p)%p)
+		}
+		testEquals(ys, e, t)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	for it := 0; it < 30; it++ {
+		n := rand.Intn(300) + 1
+		seen := map[int64]bool{}
+		var xs []int64
+		for len(xs) < n {
+			if x := rand.Int63n(p); !seen[x] {
+				seen[x] = true
+				xs = append(xs, x)
+			}
+		}
+		a := randomPoly(n)
+		ys, _ := ntt998.Evaluate(a, xs)
+		b, err := ntt998.Interpolate(xs, ys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testEquals(b, a, t)
+	}
+	if _, err := Interpolate([]int64{1, 2, 1}, []int64{1, 2, 3}); err == nil {
+		t.Errorf("Interpolate with duplicate points: expected an error.")
+	}
+	if _, err := Interpolate([]int64{1, 2}, []int64{1}); err == nil {
+		t.Errorf("Interpolate with 2 points and 1 value: expected an error.")
+	}
+	a, err := Interpolate([]int64{0, 1, 2}, []int64{1, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testEquals(a, Poly{1, 0, 1}, t)
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	a := randomPoly(1 << 14)
+	xs := randomSlice(1<<14, 0, p)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ntt998.Evaluate(a, xs)
+	}
+}
@@ -0,0 +1,300 @@
+package fft
+
+import (
+	"fmt"
+
+	"github.com/kelvinlau/go/number"
+)
+
+// The formal power series operations below are modulo x^n and the modulus of
+// the NTT. They are computed by Newton's iteration, doubling the precision
+// each round, so they take O(n log(n)) time. They return an error if 2n
+// exceeds MaxLen, or the series is not invertible, etc.
+
+// seriesLen returns an error if the series of length n are too long.
+func (t *NTT) seriesLen(n int) error {
+	if l := 2 * int(nextPowerOf2(int64(n))); l > t.maxLen {
+		return fmt.Errorf("fft: series of length %d exceeds %d supported by modulus %d", n, t.maxLen/2, t.mod)
+	}
+	return nil
+}
+
+// pad returns a % mod truncated or padded with zeros to length n.
+func (t *NTT) pad(a Poly, n int) Poly {
+	r := make(Poly, n)
+	for i := 0; i < n && i < len(a); i++ {
+		if r[i] = a[i] % t.mod; r[i] < 0 {
+			r[i] += t.mod
+		}
+	}
+	return r
+}
+
+// mulTrunc returns a * b mod x^n.
+func (t *NTT) mulTrunc(a, b Poly, n int) Poly {
+	if len(a) > n {
+		a = a[:n]
+	}
+	if len(b) > n {
+		b = b[:n]
+	}
+	return t.pad(t.Convolve(a, b), n)
+}
+
+// Inv returns b such that a * b == 1 mod x^n, where a[0] != 0.
+func (t *NTT) Inv(a Poly, n int) (Poly, error) {
+	if err := t.seriesLen(n); err != nil {
+		return nil, err
+	}
+	a = t.pad(a, n)
+	if n == 0 {
+		return a, nil
+	}
+	if a[0] == 0 {
+		return nil, fmt.Errorf("fft: series with a zero constant term is not invertible")
+	}
+	// b = b * (2 - a * b).
+	b := Poly{number.ModularInvert(a[0], t.mod)}
+	for m := 1; m < n; m *= 2 {
+		c := t.mulTrunc(a, b, 2*m)
+		for i := range c {
+			c[i] = (t.mod - c[i]) % t.mod
+		}
+		c[0] = (c[0] + 2) % t.mod
+		b = t.mulTrunc(b, c, 2*m)
+	}
+	return b[:n], nil
+}
+
+// DivMod returns q and r such that a == b * q + r, where len(r) < len(b)
+// after the trailing zeros of b are trimmed, which shall not be all zeros.
+func (t *NTT) DivMod(a, b Poly) (q, r Poly, err error) {
+	a = t.pad(a, len(a))
+	b = t.pad(b, len(b))
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	if len(b) == 0 {
+		return nil, nil, fmt.Errorf("fft: division by zero polynomial")
+	}
+	if len(a) < len(b) {
+		return Poly{}, a, nil
+	}
+	// The reversed quotient is the reversed a over the reversed b, mod
+	// x^(len(a)-len(b)+1).
+	n := len(a) - len(b) + 1
+	// The remainder is b * q mod x^(len(b)-1), where q is truncated too.
+	m := len(b) - 1
+	if n < m {
+		m = n
+	}
+	if l := len(b) - 1 + m - 1; l > t.maxLen {
+		return nil, nil, fmt.Errorf("fft: remainder product of length %d exceeds %d supported by modulus %d", l, t.maxLen, t.mod)
+	}
+	ib, err := t.Inv(reversed(b), n)
+	if err != nil {
+		return nil, nil, err
+	}
+	q = reversed(t.mulTrunc(reversed(a), ib, n))
+	r = t.mulTrunc(b, q, len(b)-1)
+	for i := range r {
+		r[i] = (a[i] - r[i] + t.mod) % t.mod
+	}
+	return q, r, nil
+}
+
+func reversed(a Poly) Poly {
+	r := make(Poly, len(a))
+	for i, x := range a {
+		r[len(a)-1-i] = x
+	}
+	return r
+}
+
+// Derivative returns the derivative of a.
+func (t *NTT) Derivative(a Poly) Poly {
+	if len(a) == 0 {
+		return Poly{}
+	}
+	a = t.pad(a, len(a))
+	r := make(Poly, len(a)-1)
+	for i := range r {
+		r[i] = a[i+1] * int64(i+1) % t.mod
+	}
+	return r
+}
+
+// Integral returns the integral of a, whose constant term is 0, where len(a)
+// shall be less than the modulus.
+func (t *NTT) Integral(a Poly) (Poly, error) {
+	n := len(a)
+	if int64(n) >= t.mod {
+		return nil, fmt.Errorf("fft: integral of length %d is not defined modulo %d", n, t.mod)
+	}
+	a = t.pad(a, n)
+	inv := t.inverses(n)
+	r := make(Poly, n+1)
+	for i := range a {
+		r[i+1] = a[i] * inv[i+1] % t.mod
+	}
+	return r, nil
+}
+
+// inverses returns the modular inverses of [1, n], where inv[0] is unused.
+func (t *NTT) inverses(n int) []int64 {
+	inv := make([]int64, n+1)
+	if n > 0 {
+		inv[1] = 1
+	}
+	for i := int64(2); i <= int64(n); i++ {
+		inv[i] = (t.mod - t.mod/i) * inv[t.mod%i] % t.mod
+	}
+	return inv
+}
+
+// Log returns the logarithm of a mod x^n, where a[0] == 1, as the integral of
+// a' / a.
+func (t *NTT) Log(a Poly, n int) (Poly, error) {
+	if err := t.seriesLen(n); err != nil {
+		return nil, err
+	}
+	a = t.pad(a, n)
+	if n == 0 {
+		return a, nil
+	}
+	if a[0] != 1 {
+		return nil, fmt.Errorf("fft: logarithm of series with constant term %d != 1", a[0])
+	}
+	ia, err := t.Inv(a, n)
+	if err != nil {
+		return nil, err
+	}
+	return t.Integral(t.mulTrunc(t.Derivative(a), ia, n-1))
+}
+
+// Exp returns the exponential of a mod x^n, where a[0] == 0.
+func (t *NTT) Exp(a Poly, n int) (Poly, error) {
+	if err := t.seriesLen(n); err != nil {
+		return nil, err
+	}
+	a = t.pad(a, n)
+	if n == 0 {
+		return a, nil
+	}
+	if a[0] != 0 {
+		return nil, fmt.Errorf("fft: exponential of series with constant term %d != 0", a[0])
+	}
+	// b = b * (1 - log(b) + a).
+	b := Poly{1}
+	for m := 1; m < n; m *= 2 {
+		l, err := t.Log(b, 2*m)
+		if err != nil {
+			return nil, err
+		}
+		c := t.pad(a, 2*m)
+		for i := range c {
+			c[i] = (c[i] - l[i] + t.mod) % t.mod
+		}
+		c[0] = (c[0] + 1) % t.mod
+		b = t.mulTrunc(b, c, 2*m)
+	}
+	return b[:n], nil
+}
+
+// Sqrt returns b such that b * b == a mod x^n, whose lowest nonzero term is
+// the least square root of the one of a, or an error if none.
+func (t *NTT) Sqrt(a Poly, n int) (Poly, error) {
+	if err := t.seriesLen(n); err != nil {
+		return nil, err
+	}
+	// Only the terms below x^n matter, so the lowest nonzero term is looked
+	// for in them.
+	if len(a) > n {
+		a = a[:n]
+	}
+	a = t.pad(a, len(a))
+	s := 0
+	for s < len(a) && a[s] == 0 {
+		s++
+	}
+	if s == len(a) {
+		return make(Poly, n), nil
+	}
+	if s%2 != 0 {
+		return nil, fmt.Errorf("fft: series with the lowest term of odd degree %d has no square root", s)
+	}
+	r := number.ModularSqrt(a[s], t.mod)
+	if r == -1 {
+		return nil, fmt.Errorf("fft: %d is not a quadratic residue modulo %d", a[s], t.mod)
+	}
+	// b = (b + a / b) / 2, on a shifted by s.
+	m := n - s/2
+	a = a[s:]
+	b := Poly{r}
+	inv2 := (t.mod + 1) / 2
+	for k := 1; k < m; k *= 2 {
+		ib, err := t.Inv(b, 2*k)
+		if err != nil {
+			return nil, err
+		}
+		c := t.mulTrunc(t.pad(a, 2*k), ib, 2*k)
+		b = t.pad(b, 2*k)
+		for i := range b {
+			b[i] = (b[i] + c[i]) % t.mod * inv2 % t.mod
+		}
+	}
+	res := make(Poly, n)
+	copy(res[s/2:], b[:m])
+	return res, nil
+}
+
+// Pow returns a^k mod x^n for k >= 0, as exp(k * log(a)) on a shifted and
+// scaled to have the constant term 1.
+func (t *NTT) Pow(a Poly, k int64, n int) (Poly, error) {
+	if err := t.seriesLen(n); err != nil {
+		return nil, err
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("fft: negative exponent %d", k)
+	}
+	a = t.pad(a, len(a))
+	res := make(Poly, n)
+	if k == 0 {
+		if n > 0 {
+			res[0] = 1
+		}
+		return res, nil
+	}
+	s := 0
+	for s < len(a) && a[s] == 0 {
+		s++
+	}
+	// The result is 0 if s*k >= n, i.e. s >= ceil(n/k), without overflows.
+	if s == len(a) || s > 0 && int64(s) >= (int64(n)-1)/k+1 {
+		return res, nil
+	}
+	m := n - s*int(k)
+	c := a[s]
+	ic := number.ModularInvert(c, t.mod)
+	b := t.pad(a[s:], m)
+	for i := range b {
+		b[i] = b[i] * ic % t.mod
+	}
+	l, err := t.Log(b, m)
+	if err != nil {
+		return nil, err
+	}
+	km := k % t.mod
+	for i := range l {
+		l[i] = l[i] * km % t.mod
+	}
+	e, err := t.Exp(l, m)
+	if err != nil {
+		return nil, err
+	}
+	ck := number.ModularPower(c, k, t.mod)
+	for i := range e {
+		res[s*int(k)+i] = e[i] * ck % t.mod
+	}
+	return res, nil
+}
@@ -0,0 +1,199 @@
+package fft
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/number"
+)
+
+const p = 998244353
+
+var ntt998 = mustNTT(p, 3)
+
+func randomPoly(n int) Poly {
+	return Poly(randomSlice(n, 0, p))
+}
+
+// mulBrute returns a * b mod x^n.
+func mulBrute(a, b Poly, n int) Poly {
+	c := make(Poly, n)
+	for i := range a {
+		for j := range b {
+			if i+j < n {
+				c[i+j] = (c[i+j] + a[i]*b[j]) % p
+			}
+		}
+	}
+	return c
+}
+
+func TestInv(t *testing.T) {
+	for it := 0; it < 50; it++ {
+		n := rand.Intn(200) + 1
+		a := randomPoly(rand.Intn(200) + 1)
+		a[0] = rand.Int63n(p-1) + 1
+		b, err := ntt998.Inv(a, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := make(Poly, n)
+		e[0] = 1
+		testEquals(mulBrute(a, b, n), e, t)
+	}
+	if _, err := ntt998.Inv(Poly{0, 1}, 2); err == nil {
+		t.Errorf("Inv(x): expected an error.")
+	}
+	if _, err := ntt998.Inv(Poly{1}, 1<<23); err == nil {
+		t.Errorf("Inv of length 2^23: expected an error.")
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	for it := 0; it < 50; it++ {
+		a := randomPoly(rand.Intn(200) + 1)
+		b := randomPoly(rand.Intn(100) + 1)
+		b[len(b)-1] = rand.Int63n(p-1) + 1
+		q, r, err := ntt998.DivMod(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) >= len(b) {
+			t.Fatalf("DivMod: remainder of length %d >= %d.", len(r), len(b))
+		}
+		c := mulBrute(b, q, len(a))
+		for i := range r {
+			c[i] = (c[i] + r[i]) % p
+		}
+		testEquals(c, a, t)
+	}
+	if _, _, err := ntt998.DivMod(Poly{1, 2}, Poly{0, 0}); err == nil {
+		t.Errorf("DivMod by 0: expected an error.")
+	}
+	// The quotient fits, but the remainder product is longer than 512.
+	n, _ := NewNTT(7681, 0)
+	b := make(Poly, 512)
+	b[0], b[511] = 1, 1
+	if _, _, err := n.DivMod(make(Poly, 639), b); err == nil {
+		t.Errorf("DivMod with a long remainder product: expected an error.")
+	}
+}
+
+func TestLogExp(t *testing.T) {
+	for it := 0; it < 30; it++ {
+		n := rand.Intn(150) + 1
+		a := randomPoly(n)
+		a[0] = 0
+		e, err := ntt998.Exp(a, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// exp(a)' == a' * exp(a).
+		testEquals(ntt998.Derivative(e), mulBrute(ntt998.Derivative(a), e, n-1), t)
+		l, err := ntt998.Log(e, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testEquals(l, a, t)
+	}
+	if _, err := ntt998.Log(Poly{2}, 1); err == nil {
+		t.Errorf("Log(2): expected an error.")
+	}
+	if _, err := ntt998.Exp(Poly{1}, 1); err == nil {
+		t.Errorf("Exp(1): expected an error.")
+	}
+}
+
+func TestDerivativeIntegral(t *testing.T) {
+	a := Poly{5, 3, 2, -1}
+	testEquals(ntt998.Derivative(a), Poly{3, 4, p - 3}, t)
+	b, err := ntt998.Integral(ntt998.Derivative(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testEquals(b, Poly{0, 3, 2, p - 1}, t)
+	n, _ := NewNTT(7, 0)
+	if _, err := n.Integral(make(Poly, 7)); err == nil {
+		t.Errorf("Integral of length 7 modulo 7: expected an error.")
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	for it := 0; it < 30; it++ {
+		n := rand.Intn(150) + 1
+		b := randomPoly(rand.Intn(100) + 1)
+		s := rand.Intn(4)
+		b = append(make(Poly, s), b...)
+		b[s] = rand.Int63n(p-1) + 1
+		a := mulBrute(b, b, n)
+		r, err := ntt998.Sqrt(a, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testEquals(mulBrute(r, r, n), a, t)
+	}
+	r, err := ntt998.Sqrt(Poly{0, 0, 0, 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testEquals(r, Poly{0, 0}, t)
+	if _, err := ntt998.Sqrt(Poly{0, 1}, 2); err == nil {
+		t.Errorf("Sqrt(x): expected an error.")
+	}
+	for x := int64(2); ; x++ {
+		if number.ModularSqrt(x, p) == -1 {
+			if _, err := ntt998.Sqrt(Poly{x}, 2); err == nil {
+				t.Errorf("Sqrt(%d): expected an error.", x)
+			}
+			break
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	for it := 0; it < 30; it++ {
+		n := rand.Intn(100) + 1
+		a := randomPoly(rand.Intn(50) + 1)
+		if it%3 == 0 {
+			a = append(make(Poly, rand.Intn(3)), a...)
+		}
+		k := rand.Int63n(10)
+		e := make(Poly, n)
+		e[0] = 1
+		for i := int64(0); i < k; i++ {
+			e = mulBrute(e, a, n)
+		}
+		r, err := ntt998.Pow(a, k, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testEquals(r, e, t)
+	}
+	r, _ := ntt998.Pow(Poly{0, 1}, 1<<40, 5)
+	testEquals(r, make(Poly, 5), t)
+	r, _ = ntt998.Pow(Poly{1, 1}, p+1, 3)
+	testEquals(r, Poly{1, (p + 1) % p, 0}, t)
+	// (2 + x)^k == 2^k * (1 + k/2 x + ...), for a huge k.
+	k := int64(math.MaxInt64)
+	r, err := ntt998.Pow(Poly{2, 1}, k, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := number.ModularPower(2, k, p)
+	if r[0] != c || r[1] != k%p*c%p*number.ModularInvert(2, p)%p {
+		t.Errorf("Pow(2 + x, %d): got %v.", k, r)
+	}
+	if _, err := ntt998.Pow(Poly{1}, -1, 1); err == nil {
+		t.Errorf("Pow(1, -1): expected an error.")
+	}
+}
+
+func BenchmarkExp(b *testing.B) {
+	a := randomPoly(1 << 16)
+	a[0] = 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ntt998.Exp(a, len(a))
+	}
+}
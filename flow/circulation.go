@@ -0,0 +1,59 @@
+package flow
+
+import "fmt"
+
+// Circulation finds circulations with lower and upper bounds on the edges.
+type Circulation struct {
+	n  int
+	es []bounded
+	g  *Graph // The reduced network of the last Feasible call.
+}
+
+type bounded struct {
+	u, v   int
+	lo, hi int64
+}
+
+// NewCirculation returns a circulation problem of n vertices and no edges.
+func NewCirculation(n int) *Circulation {
+	return &Circulation{n: n}
+}
+
+// AddEdge adds a directed edge (u, v), whose flow shall be in [lo, hi], and
+// returns its id. It panics if lo > hi.
+func (c *Circulation) AddEdge(u, v int, lo, hi int64) int {
+	if lo > hi {
+		panic(fmt.Sprintf("flow: edge (%d, %d) of bounds [%d, %d]", u, v, lo, hi))
+	}
+	c.es = append(c.es, bounded{u, v, lo, hi})
+	return len(c.es) - 1
+}
+
+// Feasible reports if a circulation exists, and finds one if so. It reduces to
+// a maximum flow, where the flows forced by the lower bounds come from a super
+// source, and go to a super sink.
+func (c *Circulation) Feasible() bool {
+	s, t := c.n, c.n+1
+	c.g = NewGraph(c.n + 2)
+	ex := make([]int64, c.n) // ex[u] is the flow into u forced by the lower bounds.
+	for _, e := range c.es {
+		c.g.AddEdge(e.u, e.v, e.hi-e.lo)
+		ex[e.v] += e.lo
+		ex[e.u] -= e.lo
+	}
+	var need int64
+	for u, x := range ex {
+		if x > 0 {
+			c.g.AddEdge(s, u, x)
+			need += x
+		} else if x < 0 {
+			c.g.AddEdge(u, t, -x)
+		}
+	}
+	return c.g.Dinic(s, t) == need
+}
+
+// Flow returns the flow on edge id of the circulation found by Feasible.
+func (c *Circulation) Flow(id int) int64 {
+	return c.es[id].lo + c.g.Flow(2*id)
+}
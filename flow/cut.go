@@ -0,0 +1,161 @@
+package flow
+
+import (
+	"github.com/kelvinlau/go/graph"
+	"github.com/kelvinlau/go/lca"
+)
+
+// GlobalMinCut returns the minimum cut of an undirected graph of n >= 2
+// vertices and non-negative weights, by Stoer-Wagner in O(n^3), where side[u]
+// reports if u is on one side of the cut.
+func GlobalMinCut(n int, es []graph.Edge) (w int64, side []bool) {
+	mat := make([][]int64, n)
+	for u := range mat {
+		mat[u] = make([]int64, n)
+	}
+	for _, e := range es {
+		if e.U != e.V {
+			mat[e.U][e.V] += e.W
+			mat[e.V][e.U] += e.W
+		}
+	}
+	// group[u] are the vertices merged into u, for the active u.
+	group := make([][]int, n)
+	for u := range group {
+		group[u] = []int{u}
+	}
+	active := make([]bool, n)
+	for u := range active {
+		active[u] = true
+	}
+	w = -1
+	var best []int
+	conn := make([]int64, n)
+	added := make([]bool, n)
+	for m := n; m > 1; m-- {
+		// Add the most tightly connected vertex one by one, then the last two
+		// are s and t, and the cut of the phase separates t from the rest.
+		for u := range conn {
+			conn[u], added[u] = 0, false
+		}
+		s, t := -1, -1
+		for k := 0; k < m; k++ {
+			x := -1
+			for u := 0; u < n; u++ {
+				if active[u] && !added[u] && (x == -1 || conn[u] > conn[x]) {
+					x = u
+				}
+			}
+			added[x] = true
+			s, t = t, x
+			for u := 0; u < n; u++ {
+				conn[u] += mat[x][u]
+			}
+		}
+		if w == -1 || conn[t] < w {
+			w = conn[t]
+			best = append(best[:0], group[t]...)
+		}
+		group[s] = append(group[s], group[t]...)
+		active[t] = false
+		for u := 0; u < n; u++ {
+			mat[s][u] += mat[t][u]
+			mat[u][s] = mat[s][u]
+		}
+		mat[s][s] = 0
+	}
+	side = make([]bool, n)
+	for _, u := range best {
+		side[u] = true
+	}
+	return w, side
+}
+
+// GomoryHu is a Gomory-Hu tree of an undirected graph, where the minimum cut
+// between any u and v is the min edge weight on the tree path between them.
+type GomoryHu struct {
+	n   int
+	pnt []int   // pnt[u] is the parent of u, except for the root 0.
+	w   []int64 // w[u] is the weight of the edge (u, pnt[u]).
+	t   *lca.Tree
+}
+
+// NewGomoryHu returns the Gomory-Hu tree of an undirected graph of n vertices
+// and non-negative weights, by Gusfield's algorithm of n-1 max flows.
+func NewGomoryHu(n int, es []graph.Edge) *GomoryHu {
+	g := NewGraph(n)
+	for _, e := range es {
+		g.AddEdge(e.U, e.V, e.W)
+		g.AddEdge(e.V, e.U, e.W)
+	}
+	h := &GomoryHu{
+		n:   n,
+		pnt: make([]int, n),
+		w:   make([]int64, n),
+		t:   lca.NewTree(n),
+	}
+	for u := 1; u < n; u++ {
+		p := h.pnt[u]
+		g.Reset()
+		f := g.Dinic(u, p)
+		h.w[u] = f
+		side := g.MinCut(u)
+		for v := 0; v < n; v++ {
+			if v != u && side[v] && h.pnt[v] == p {
+				h.pnt[v] = u
+			}
+		}
+		// Keep the tree edges being the minimum cuts, not only of the same
+		// values, by moving u above p if p's parent is on u's side.
+		if q := h.pnt[p]; p != 0 && side[q] {
+			h.pnt[u], h.pnt[p] = q, u
+			h.w[u], h.w[p] = h.w[p], f
+		}
+	}
+	for u := 1; u < n; u++ {
+		h.t.LinkWeighted(u, h.pnt[u], h.w[u])
+	}
+	if n > 0 {
+		h.t.Build(0)
+	}
+	return h
+}
+
+// Edges returns the edges of the tree.
+func (h *GomoryHu) Edges() []graph.Edge {
+	es := make([]graph.Edge, 0, h.n)
+	for u := 1; u < h.n; u++ {
+		es = append(es, graph.Edge{U: u, V: h.pnt[u], W: h.w[u]})
+	}
+	return es
+}
+
+// MinCut returns the minimum cut between u != v, in O(log(n)).
+func (h *GomoryHu) MinCut(u, v int) int64 {
+	w, _ := h.t.PathMin(u, v)
+	return w
+}
+
+// Cut returns a minimum cut between u != v, where side[x] reports if x is on
+// the side of u, in O(n log(n)).
+func (h *GomoryHu) Cut(u, v int) (side []bool) {
+	// Cut the lightest edge (c, pnt[c]) on the tree path.
+	z, _ := h.t.Lca(u, v)
+	c := -1
+	for _, x := range []int{u, v} {
+		for ; x != z; x = h.pnt[x] {
+			if c == -1 || h.w[x] < h.w[c] {
+				c = x
+			}
+		}
+	}
+	in := func(x int) bool {
+		y, _ := h.t.Lca(x, c)
+		return y == c
+	}
+	side = make([]bool, h.n)
+	for x := range side {
+		side[x] = in(x) == in(u)
+	}
+	return side
+}
@@ -0,0 +1,86 @@
+package flow
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/graph"
+)
+
+func randomUndirected(n, m int) []graph.Edge {
+	es := make([]graph.Edge, m)
+	for i := range es {
+		es[i] = graph.Edge{U: rand.Intn(n), V: rand.Intn(n), W: rand.Int63n(20)}
+	}
+	return es
+}
+
+// cutWeight returns the weight of the edges across the cut.
+func cutWeight(es []graph.Edge, side []bool) (w int64) {
+	for _, e := range es {
+		if side[e.U] != side[e.V] {
+			w += e.W
+		}
+	}
+	return w
+}
+
+func TestGlobalMinCut(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(9) + 2
+		es := randomUndirected(n, rand.Intn(20))
+		expected := int64(-1)
+		for mask := 1; mask < 1<<uint(n)-1; mask++ {
+			side := make([]bool, n)
+			for u := range side {
+				side[u] = mask>>uint(u)&1 == 1
+			}
+			if w := cutWeight(es, side); expected == -1 || w < expected {
+				expected = w
+			}
+		}
+		w, side := GlobalMinCut(n, es)
+		if w != expected {
+			t.Fatalf("GlobalMinCut(%v): expected %d, got %d.", es, expected, w)
+		}
+		k := 0
+		for _, s := range side {
+			if s {
+				k++
+			}
+		}
+		if k == 0 || k == n || cutWeight(es, side) != w {
+			t.Fatalf("GlobalMinCut(%v): invalid cut %v.", es, side)
+		}
+	}
+}
+
+func TestGomoryHu(t *testing.T) {
+	for it := 0; it < 200; it++ {
+		n := rand.Intn(8) + 1
+		es := randomUndirected(n, rand.Intn(15))
+		var es3 []edge3
+		for _, e := range es {
+			es3 = append(es3, edge3{e.U, e.V, e.W}, edge3{e.V, e.U, e.W})
+		}
+		h := NewGomoryHu(n, es)
+		if len(h.Edges()) != n-1 {
+			t.Fatalf("NewGomoryHu(%v): expected %d edges, got %v.", es, n-1, h.Edges())
+		}
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if u == v {
+					continue
+				}
+				expected := bruteMinCut(n, es3, u, v)
+				if w := h.MinCut(u, v); w != expected {
+					t.Fatalf("MinCut(%d, %d) of %v: expected %d, got %d.", u, v, es, expected, w)
+				}
+				side := h.Cut(u, v)
+				if !side[u] || side[v] || cutWeight(es, side) != expected {
+					t.Fatalf("Cut(%d, %d) of %v: invalid cut %v.", u, v, es, side)
+				}
+			}
+		}
+	}
+}
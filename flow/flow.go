@@ -0,0 +1,242 @@
+// Package flow implements maximum flow algorithms: Dinic, and highest-label
+// push-relabel, along with minimum cuts, and circulations with lower bounds;
+// minimum cost flow algorithms: successive shortest paths, and network
+// simplex; and cuts of undirected graphs: Stoer-Wagner global minimum cut, and
+// Gomory-Hu trees.
+package flow
+
+// Graph is a flow network. Each edge added is paired with a reverse edge of
+// zero capacity, where edge id^1 is the reverse of edge id.
+type Graph struct {
+	n   int
+	es  []edge
+	adj [][]int // adj[u] are the ids of the edges from u.
+}
+
+type edge struct {
+	to              int
+	cap, flow, cost int64
+}
+
+// NewGraph returns a flow network of n vertices and no edges.
+func NewGraph(n int) *Graph {
+	return &Graph{
+		n:   n,
+		adj: make([][]int, n),
+	}
+}
+
+// AddEdge adds a directed edge (u, v) of capacity c, and returns its id.
+func (g *Graph) AddEdge(u, v int, c int64) int {
+	return g.AddCostEdge(u, v, c, 0)
+}
+
+// AddCostEdge adds a directed edge (u, v) of capacity c, and of cost w per
+// unit of flow, and returns its id.
+func (g *Graph) AddCostEdge(u, v int, c, w int64) int {
+	id := len(g.es)
+	g.es = append(g.es, edge{to: v, cap: c, cost: w}, edge{to: u, cost: -w})
+	g.adj[u] = append(g.adj[u], id)
+	g.adj[v] = append(g.adj[v], id^1)
+	return id
+}
+
+// Flow returns the flow on edge id.
+func (g *Graph) Flow(id int) int64 {
+	return g.es[id].flow
+}
+
+// Cost returns the total cost of the flows on all edges.
+func (g *Graph) Cost() (w int64) {
+	for id := 0; id < len(g.es); id += 2 {
+		w += g.es[id].flow * g.es[id].cost
+	}
+	return w
+}
+
+// Reset clears the flows on all edges.
+func (g *Graph) Reset() {
+	for i := range g.es {
+		g.es[i].flow = 0
+	}
+}
+
+func (g *Graph) residual(id int) int64 {
+	return g.es[id].cap - g.es[id].flow
+}
+
+func (g *Graph) push(id int, f int64) {
+	g.es[id].flow += f
+	g.es[id^1].flow -= f
+}
+
+// Dinic augments the current flow to a maximum flow from s to t by Dinic's
+// algorithm, in O(n^2 m) time, and returns the value augmented, which is 0 if
+// s == t.
+func (g *Graph) Dinic(s, t int) (f int64) {
+	if s == t {
+		return 0
+	}
+	level := make([]int, g.n)
+	it := make([]int, g.n)
+	q := make([]int, 0, g.n)
+	bfs := func() bool {
+		for u := range level {
+			level[u] = -1
+		}
+		level[s] = 0
+		q = append(q[:0], s)
+		for i := 0; i < len(q); i++ {
+			u := q[i]
+			for _, id := range g.adj[u] {
+				if v := g.es[id].to; level[v] == -1 && g.residual(id) > 0 {
+					level[v] = level[u] + 1
+					q = append(q, v)
+				}
+			}
+		}
+		return level[t] != -1
+	}
+
+	var path []int // The edges from s to u.
+	for bfs() {
+		for u := range it {
+			it[u] = 0
+		}
+		path = path[:0]
+		for u := s; ; {
+			if u == t {
+				// Push the bottleneck, and retreat to the first saturated edge.
+				d := g.residual(path[0])
+				for _, id := range path {
+					if r := g.residual(id); r < d {
+						d = r
+					}
+				}
+				k := -1
+				for i, id := range path {
+					g.push(id, d)
+					if k == -1 && g.residual(id) == 0 {
+						k = i
+					}
+				}
+				f += d
+				u = g.es[path[k]^1].to
+				path = path[:k]
+				continue
+			}
+			if it[u] < len(g.adj[u]) {
+				id := g.adj[u][it[u]]
+				if v := g.es[id].to; level[v] == level[u]+1 && g.residual(id) > 0 {
+					path = append(path, id)
+					u = v
+				} else {
+					it[u]++
+				}
+				continue
+			}
+			// u is a dead end.
+			if u == s {
+				break
+			}
+			level[u] = -1
+			id := path[len(path)-1]
+			path = path[:len(path)-1]
+			u = g.es[id^1].to
+			it[u]++
+		}
+	}
+	return f
+}
+
+// PushRelabel augments the current flow to a maximum flow from s to t by the
+// highest-label push-relabel algorithm with the gap heuristic, in
+// O(n^2 sqrt(m)) time, and returns the value augmented, which is 0 if s == t.
+func (g *Graph) PushRelabel(s, t int) int64 {
+	if s == t {
+		return 0
+	}
+	n := g.n
+	h := make([]int, n)
+	ex := make([]int64, n)
+	cur := make([]int, n)
+	cnt := make([]int, 2*n)  // cnt[d] is the num of vertices of height d.
+	hs := make([][]int, 2*n) // hs[d] are the active vertices of height d.
+	h[s] = n
+	ex[t] = 1 // So t is never active.
+	cnt[0] = n - 1
+	push := func(id int, f int64) {
+		v := g.es[id].to
+		if ex[v] == 0 && f > 0 {
+			hs[h[v]] = append(hs[h[v]], v)
+		}
+		g.push(id, f)
+		ex[v] += f
+		ex[g.es[id^1].to] -= f
+	}
+	for _, id := range g.adj[s] {
+		push(id, g.residual(id))
+	}
+	for hi := 0; ; {
+		for len(hs[hi]) == 0 {
+			if hi == 0 {
+				return -ex[s]
+			}
+			hi--
+		}
+		u := hs[hi][len(hs[hi])-1]
+		hs[hi] = hs[hi][:len(hs[hi])-1]
+		for ex[u] > 0 {
+			if cur[u] == len(g.adj[u]) {
+				// Relabel u.
+				h[u] = 2 * n
+				for i, id := range g.adj[u] {
+					if v := g.es[id].to; g.residual(id) > 0 && h[v]+1 < h[u] {
+						h[u], cur[u] = h[v]+1, i
+					}
+				}
+				cnt[h[u]]++
+				if cnt[hi]--; cnt[hi] == 0 && hi < n {
+					// Gap: the vertices above hi can't reach t any more.
+					for v := range h {
+						if hi < h[v] && h[v] < n {
+							cnt[h[v]]--
+							h[v] = n + 1
+						}
+					}
+				}
+				hi = h[u]
+			} else if id := g.adj[u][cur[u]]; g.residual(id) > 0 && h[u] == h[g.es[id].to]+1 {
+				push(id, min64(ex[u], g.residual(id)))
+			} else {
+				cur[u]++
+			}
+		}
+	}
+}
+
+// MinCut returns the minimum cut after a maximum flow from s, where side[u]
+// reports if u is on the side of s.
+func (g *Graph) MinCut(s int) (side []bool) {
+	side = make([]bool, g.n)
+	side[s] = true
+	stack := []int{s}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, id := range g.adj[u] {
+			if v := g.es[id].to; !side[v] && g.residual(id) > 0 {
+				side[v] = true
+				stack = append(stack, v)
+			}
+		}
+	}
+	return side
+}
+
+func min64(x, y int64) int64 {
+	if x < y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,309 @@
+package flow
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type edge3 struct {
+	u, v int
+	c    int64
+}
+
+func randomEdges(n, m int) []edge3 {
+	es := make([]edge3, m)
+	for i := range es {
+		es[i] = edge3{rand.Intn(n), rand.Intn(n), rand.Int63n(20)}
+	}
+	return es
+}
+
+// bruteMinCut returns the min cut capacity from s to t over all partitions.
+func bruteMinCut(n int, es []edge3, s, t int) int64 {
+	best := int64(-1)
+	for mask := 0; mask < 1<<uint(n); mask++ {
+		if mask&(1<<uint(s)) == 0 || mask&(1<<uint(t)) != 0 {
+			continue
+		}
+		var c int64
+		for _, e := range es {
+			if mask&(1<<uint(e.u)) != 0 && mask&(1<<uint(e.v)) == 0 {
+				c += e.c
+			}
+		}
+		if best == -1 || c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+func checkFlow(t *testing.T, g *Graph, es []edge3, s, tt int, f int64) {
+	ex := make([]int64, g.n)
+	for i, e := range es {
+		x := g.Flow(2 * i)
+		if x < 0 || x > e.c {
+			t.Fatalf("Flow %d on edge %v out of capacity.", x, e)
+		}
+		ex[e.u] -= x
+		ex[e.v] += x
+	}
+	for u, x := range ex {
+		if u == s && x != -f || u == tt && x != f || u != s && u != tt && x != 0 {
+			t.Fatalf("Flow not conserved at %d: %v.", u, ex)
+		}
+	}
+	side := g.MinCut(s)
+	var c int64
+	for _, e := range es {
+		if side[e.u] && !side[e.v] {
+			c += e.c
+		}
+	}
+	if !side[s] || side[tt] || c != f {
+		t.Fatalf("MinCut: expected capacity %d, got %d, %v.", f, c, side)
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	algos := map[string]func(g *Graph, s, t int) int64{
+		"Dinic":       (*Graph).Dinic,
+		"PushRelabel": (*Graph).PushRelabel,
+	}
+	for i := 0; i < 300; i++ {
+		n := 2 + rand.Intn(8)
+		es := randomEdges(n, rand.Intn(4*n))
+		s, tt := 0, n-1
+		e := bruteMinCut(n, es, s, tt)
+		for name, algo := range algos {
+			g := NewGraph(n)
+			for _, e := range es {
+				g.AddEdge(e.u, e.v, e.c)
+			}
+			if f := algo(g, s, tt); f != e {
+				t.Fatalf("%s(%v): expected %d, got %d.", name, es, e, f)
+			}
+			checkFlow(t, g, es, s, tt, e)
+		}
+	}
+}
+
+func TestSameSourceSink(t *testing.T) {
+	g := NewGraph(2)
+	g.AddEdge(0, 1, 3)
+	g.AddEdge(1, 0, 3)
+	if f := g.Dinic(1, 1); f != 0 {
+		t.Errorf("Dinic(1, 1): expected 0, got %d.", f)
+	}
+	if f := g.PushRelabel(1, 1); f != 0 {
+		t.Errorf("PushRelabel(1, 1): expected 0, got %d.", f)
+	}
+}
+
+func TestCirculationBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("AddEdge(0, 1, 5, 3): expected a panic.")
+		}
+	}()
+	c := NewCirculation(2)
+	c.AddEdge(1, 0, 0, 10)
+	c.AddEdge(0, 1, 5, 3)
+}
+
+func TestAugmentFlow(t *testing.T) {
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 3)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(1, 3, 2)
+	g.AddEdge(2, 3, 3)
+	id := g.AddEdge(1, 2, 5)
+	if f := g.Dinic(0, 3); f != 5 {
+		t.Errorf("Dinic: expected 5, got %d.", f)
+	}
+	if g.Flow(id) != 1 {
+		t.Errorf("Flow(%d): expected 1, got %d.", id, g.Flow(id))
+	}
+	// Augmenting a maximum flow adds nothing.
+	if f := g.PushRelabel(0, 3); f != 0 {
+		t.Errorf("PushRelabel: expected 0, got %d.", f)
+	}
+	g.Reset()
+	if f := g.PushRelabel(0, 3); f != 5 {
+		t.Errorf("PushRelabel: expected 5, got %d.", f)
+	}
+}
+
+func TestCirculation(t *testing.T) {
+	c := NewCirculation(3)
+	c.AddEdge(0, 1, 2, 5)
+	c.AddEdge(1, 2, 0, 3)
+	c.AddEdge(2, 0, 1, 4)
+	if !c.Feasible() {
+		t.Fatalf("Feasible: expected true.")
+	}
+	if f := c.Flow(0); f < 2 || f > 3 || c.Flow(1) != f || c.Flow(2) != f {
+		t.Errorf("Flow: got %d, %d, %d.", c.Flow(0), c.Flow(1), c.Flow(2))
+	}
+	c.AddEdge(0, 2, 4, 4)
+	if c.Feasible() {
+		t.Errorf("Feasible: expected false.")
+	}
+
+	for i := 0; i < 300; i++ {
+		n := 1 + rand.Intn(6)
+		c := NewCirculation(n)
+		type bound struct{ lo, hi int64 }
+		var es []edge3
+		var bs []bound
+		for k := rand.Intn(3 * n); k > 0; k-- {
+			e := edge3{rand.Intn(n), rand.Intn(n), 0}
+			lo := rand.Int63n(4)
+			b := bound{lo, lo + rand.Int63n(6)}
+			es, bs = append(es, e), append(bs, b)
+			c.AddEdge(e.u, e.v, b.lo, b.hi)
+		}
+		if !c.Feasible() {
+			continue
+		}
+		ex := make([]int64, n)
+		for k, e := range es {
+			f := c.Flow(k)
+			if f < bs[k].lo || f > bs[k].hi {
+				t.Fatalf("Flow(%d) = %d out of bounds %v.", k, f, bs[k])
+			}
+			ex[e.u] -= f
+			ex[e.v] += f
+		}
+		for u, x := range ex {
+			if x != 0 {
+				t.Fatalf("Circulation not conserved at %d: %v.", u, ex)
+			}
+		}
+	}
+}
+
+type costEdge struct {
+	u, v    int
+	c, cost int64
+}
+
+// bruteMinCost returns the min cost of each flow value from s to t, by
+// enumerating the integral flows.
+func bruteMinCost(n int, es []costEdge, s, t int) map[int64]int64 {
+	res := make(map[int64]int64)
+	x := make([]int64, len(es))
+	var dfs func(k int)
+	dfs = func(k int) {
+		if k == len(es) {
+			ex := make([]int64, n)
+			var cost int64
+			for i, e := range es {
+				ex[e.u] -= x[i]
+				ex[e.v] += x[i]
+				cost += x[i] * e.cost
+			}
+			for u, d := range ex {
+				if u != s && u != t && d != 0 {
+					return
+				}
+			}
+			f := ex[t]
+			if s == t {
+				f = 0
+			}
+			if c, ok := res[f]; !ok || cost < c {
+				res[f] = cost
+			}
+			return
+		}
+		for x[k] = 0; x[k] <= es[k].c; x[k]++ {
+			dfs(k + 1)
+		}
+	}
+	dfs(0)
+	return res
+}
+
+func TestMinCostFlow(t *testing.T) {
+	algos := map[string]func(g *Graph, s, t int, limit int64) (int64, int64){
+		"MinCostFlow":    (*Graph).MinCostFlow,
+		"NetworkSimplex": (*Graph).NetworkSimplex,
+	}
+	for i := 0; i < 300; i++ {
+		n := 2 + rand.Intn(4)
+		// Edges from lower to higher vertices, so no negative cycles.
+		var es []costEdge
+		for k := rand.Intn(7); k > 0; k-- {
+			u, v := rand.Intn(n), rand.Intn(n)
+			if u == v {
+				continue
+			}
+			if u > v {
+				u, v = v, u
+			}
+			es = append(es, costEdge{u, v, rand.Int63n(3), rand.Int63n(11) - 5})
+		}
+		s, tt := 0, n-1
+		best := bruteMinCost(n, es, s, tt)
+		maxFlow := int64(0)
+		for f := range best {
+			if f > maxFlow {
+				maxFlow = f
+			}
+		}
+		limit := rand.Int63n(maxFlow + 2)
+		ef := maxFlow
+		if limit < ef {
+			ef = limit
+		}
+		for name, algo := range algos {
+			g := NewGraph(n)
+			for _, e := range es {
+				g.AddCostEdge(e.u, e.v, e.c, e.cost)
+			}
+			f, c := algo(g, s, tt, limit)
+			if f != ef || c != best[ef] || g.Cost() != c {
+				t.Fatalf("%s(%v, %d): expected %d, %d, got %d, %d.", name, es, limit, ef, best[ef], f, c)
+			}
+			ex := make([]int64, n)
+			for k, e := range es {
+				x := g.Flow(2 * k)
+				if x < 0 || x > e.c {
+					t.Fatalf("%s: flow %d on edge %v out of capacity.", name, x, e)
+				}
+				ex[e.u] -= x
+				ex[e.v] += x
+			}
+			for u, x := range ex {
+				if u == s && x != -f || u == tt && x != f || u != s && u != tt && x != 0 {
+					t.Fatalf("%s: flow not conserved at %d: %v.", name, u, ex)
+				}
+			}
+		}
+	}
+}
+
+func TestMinCostFlowLarge(t *testing.T) {
+	const n = 60
+	var es []costEdge
+	for k := 0; k < 400; k++ {
+		u, v := rand.Intn(n), rand.Intn(n)
+		if u != v {
+			es = append(es, costEdge{u, v, rand.Int63n(10), rand.Int63n(100)})
+		}
+	}
+	g1, g2 := NewGraph(n), NewGraph(n)
+	for _, e := range es {
+		g1.AddCostEdge(e.u, e.v, e.c, e.cost)
+		g2.AddCostEdge(e.u, e.v, e.c, e.cost)
+	}
+	f1, c1 := g1.MinCostFlow(0, n-1, inf)
+	f2, c2 := g2.NetworkSimplex(0, n-1, inf)
+	if f1 != f2 || c1 != c2 {
+		t.Errorf("MinCostFlow got %d, %d, NetworkSimplex got %d, %d.", f1, c1, f2, c2)
+	}
+	if f := g1.Dinic(0, n-1); f != 0 {
+		t.Errorf("MinCostFlow is not maximum, Dinic augmented %d.", f)
+	}
+}
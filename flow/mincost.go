@@ -0,0 +1,335 @@
+package flow
+
+import (
+	"container/heap"
+	"math"
+)
+
+const inf = math.MaxInt64
+
+// MinCostFlow augments the current flow from s to t by successive shortest
+// paths, until the flow augmented reaches limit or is maximum, and the cost
+// augmented is minimum for the flow. Costs may be negative, as long as there
+// are no negative cycles. The shortest paths use Dijkstra's algorithm on the
+// costs reduced by Johnson potentials, which come from Bellman–Ford at first.
+// Returns the flow and the cost augmented.
+func (g *Graph) MinCostFlow(s, t int, limit int64) (flow, cost int64) {
+	n := g.n
+	pi := make([]int64, n)
+	dist := make([]int64, n)
+	pe := make([]int, n) // pe[v] is the edge into v on the shortest path.
+
+	// Bellman–Ford from s, by queue.
+	for u := range pi {
+		pi[u] = inf
+	}
+	pi[s] = 0
+	inq := make([]bool, n)
+	q := []int{s}
+	for len(q) > 0 {
+		u := q[0]
+		q = q[1:]
+		inq[u] = false
+		for _, id := range g.adj[u] {
+			e := &g.es[id]
+			if g.residual(id) > 0 && pi[u]+e.cost < pi[e.to] {
+				pi[e.to] = pi[u] + e.cost
+				if !inq[e.to] {
+					inq[e.to] = true
+					q = append(q, e.to)
+				}
+			}
+		}
+	}
+	for u := range pi {
+		if pi[u] == inf {
+			pi[u] = 0
+		}
+	}
+
+	h := &items{}
+	for flow < limit {
+		for u := range dist {
+			dist[u] = inf
+		}
+		dist[s] = 0
+		heap.Push(h, item{s, 0})
+		for h.Len() > 0 {
+			it := heap.Pop(h).(item)
+			u := it.u
+			if it.d > dist[u] {
+				continue
+			}
+			for _, id := range g.adj[u] {
+				e := &g.es[id]
+				if g.residual(id) == 0 {
+					continue
+				}
+				if d := dist[u] + e.cost + pi[u] - pi[e.to]; d < dist[e.to] {
+					dist[e.to] = d
+					pe[e.to] = id
+					heap.Push(h, item{e.to, d})
+				}
+			}
+		}
+		if dist[t] == inf {
+			break
+		}
+		for u := range pi {
+			if dist[u] != inf {
+				pi[u] += dist[u]
+			}
+		}
+		f := limit - flow
+		for v := t; v != s; v = g.es[pe[v]^1].to {
+			f = min64(f, g.residual(pe[v]))
+		}
+		for v := t; v != s; v = g.es[pe[v]^1].to {
+			g.push(pe[v], f)
+			cost += f * g.es[pe[v]].cost
+		}
+		flow += f
+	}
+	return flow, cost
+}
+
+type item struct {
+	u int
+	d int64
+}
+
+type items []item
+
+func (h items) Len() int            { return len(h) }
+func (h items) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h items) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *items) Push(x interface{}) { *h = append(*h, x.(item)) }
+func (h *items) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// NetworkSimplex is MinCostFlow by the primal network simplex algorithm on the
+// residual network, whose running time doesn't depend on the num of
+// augmenting paths. The flow is maximized by an edge (t, s) of a cost cheaper
+// than any path, and the cost is then minimized as a circulation.
+func (g *Graph) NetworkSimplex(s, t int, limit int64) (flow, cost int64) {
+	ns := newSimplex(g.n)
+	var big int64 = 1
+	for id := 0; id < len(g.es); id += 2 {
+		e := &g.es[id]
+		u := g.es[id^1].to
+		ns.addArc(u, e.to, e.cap-e.flow, e.cost)
+		ns.addArc(e.to, u, e.flow, -e.cost)
+		big += abs64(e.cost)
+	}
+	ts := ns.addArc(t, s, limit, -big)
+	ns.solve()
+	for id := 0; id < len(g.es); id += 2 {
+		if f := ns.x[id] - ns.x[id+1]; f != 0 {
+			g.push(id, f)
+			cost += f * g.es[id].cost
+		}
+	}
+	return ns.x[ts], cost
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// simplex solves minimum cost circulations by the network simplex algorithm.
+// The spanning tree is rooted at an extra vertex r, with an artificial arc
+// from each vertex to r, which never carries flow.
+type simplex struct {
+	n, r           int
+	from, to       []int
+	cap, cost, x   []int64
+	state          []int8 // 0 in the tree, 1 at the lower bound, -1 at the upper bound.
+	pnt, pe, depth []int
+	pi             []int64
+	tree           []int // The arcs in the tree.
+	adj            [][]int
+}
+
+func newSimplex(n int) *simplex {
+	return &simplex{
+		n:     n,
+		r:     n,
+		pnt:   make([]int, n+1),
+		pe:    make([]int, n+1),
+		depth: make([]int, n+1),
+		pi:    make([]int64, n+1),
+		adj:   make([][]int, n+1),
+	}
+}
+
+func (ns *simplex) addArc(u, v int, c, w int64) int {
+	ns.from = append(ns.from, u)
+	ns.to = append(ns.to, v)
+	ns.cap = append(ns.cap, c)
+	ns.cost = append(ns.cost, w)
+	ns.x = append(ns.x, 0)
+	ns.state = append(ns.state, 1)
+	return len(ns.x) - 1
+}
+
+// reduced returns the reduced cost of arc a.
+func (ns *simplex) reduced(a int) int64 {
+	return ns.cost[a] + ns.pi[ns.from[a]] - ns.pi[ns.to[a]]
+}
+
+// rebuild computes the parents, the depths and the potentials from the tree.
+func (ns *simplex) rebuild() {
+	for u := range ns.adj {
+		ns.adj[u] = ns.adj[u][:0]
+	}
+	for _, a := range ns.tree {
+		ns.adj[ns.from[a]] = append(ns.adj[ns.from[a]], a)
+		ns.adj[ns.to[a]] = append(ns.adj[ns.to[a]], a)
+	}
+	q := []int{ns.r}
+	ns.pnt[ns.r] = -1
+	ns.pe[ns.r] = -1
+	for i := 0; i < len(q); i++ {
+		u := q[i]
+		for _, a := range ns.adj[u] {
+			v := ns.from[a] ^ ns.to[a] ^ u
+			if a == ns.pe[u] {
+				continue
+			}
+			ns.pnt[v], ns.pe[v] = u, a
+			ns.depth[v] = ns.depth[u] + 1
+			// The reduced cost of a tree arc is 0.
+			if ns.from[a] == u {
+				ns.pi[v] = ns.pi[u] + ns.cost[a]
+			} else {
+				ns.pi[v] = ns.pi[u] - ns.cost[a]
+			}
+			q = append(q, v)
+		}
+	}
+}
+
+// entering returns a non-tree arc violating the optimality, by block search
+// from arc *next, or -1 if the tree is optimal.
+func (ns *simplex) entering(next *int) int {
+	m := len(ns.x)
+	block := int(math.Sqrt(float64(m))) + 1
+	best, bestV := -1, int64(0)
+	for i := 0; i < m; i++ {
+		a := (*next + i) % m
+		if v := int64(ns.state[a]) * ns.reduced(a); v < bestV {
+			best, bestV = a, v
+		}
+		if (i+1)%block == 0 && best != -1 {
+			*next = (a + 1) % m
+			return best
+		}
+	}
+	return best
+}
+
+func (ns *simplex) solve() {
+	for v := 0; v < ns.n; v++ {
+		ns.tree = append(ns.tree, ns.addArc(v, ns.r, inf, 0))
+		ns.state[len(ns.state)-1] = 0
+	}
+	ns.rebuild()
+	next := 0
+	for {
+		a := ns.entering(&next)
+		if a == -1 {
+			return
+		}
+		// Push along a if at the lower bound, or against a otherwise, around
+		// the cycle join -> ... -> u -> v -> ... -> join.
+		u, v := ns.from[a], ns.to[a]
+		if ns.state[a] == -1 {
+			u, v = v, u
+		}
+		var us, vs []int // The tree arcs from u and v up to the join.
+		for x, y := u, v; x != y; {
+			if ns.depth[x] >= ns.depth[y] {
+				us = append(us, x)
+				x = ns.pnt[x]
+			} else {
+				vs = append(vs, y)
+				y = ns.pnt[y]
+			}
+		}
+		// residual returns the residual capacity of the tree arc of x, in the
+		// direction from x to its parent if up, or the reverse otherwise.
+		residual := func(x int, up bool) int64 {
+			b := ns.pe[x]
+			if (ns.from[b] == x) == up {
+				return ns.cap[b] - ns.x[b]
+			}
+			return ns.x[b]
+		}
+
+		// Pick the last blocking arc in the cycle order, which keeps the tree
+		// strongly feasible.
+		var delta int64
+		leave := -1
+		block := func(r int64, b int) {
+			if leave == -1 || r <= delta {
+				delta, leave = r, b
+			}
+		}
+		for i := len(us) - 1; i >= 0; i-- {
+			block(residual(us[i], false), ns.pe[us[i]])
+		}
+		if ns.state[a] == 1 {
+			block(ns.cap[a]-ns.x[a], a)
+		} else {
+			block(ns.x[a], a)
+		}
+		for _, y := range vs {
+			block(residual(y, true), ns.pe[y])
+		}
+
+		// Push delta around the cycle.
+		if delta > 0 {
+			ns.x[a] += int64(ns.state[a]) * delta
+			for _, x := range us {
+				b := ns.pe[x]
+				if ns.from[b] == x {
+					ns.x[b] -= delta
+				} else {
+					ns.x[b] += delta
+				}
+			}
+			for _, y := range vs {
+				b := ns.pe[y]
+				if ns.from[b] == y {
+					ns.x[b] += delta
+				} else {
+					ns.x[b] -= delta
+				}
+			}
+		}
+
+		if leave == a {
+			ns.state[a] = -ns.state[a]
+			continue
+		}
+		ns.state[a] = 0
+		ns.state[leave] = 1
+		if ns.x[leave] != 0 {
+			ns.state[leave] = -1
+		}
+		for i, b := range ns.tree {
+			if b == leave {
+				ns.tree[i] = a
+				break
+			}
+		}
+		ns.rebuild()
+	}
+}
@@ -0,0 +1,110 @@
+package graph
+
+// lowlink holds the results of the lowlink dfs on an undirected graph.
+type lowlink struct {
+	bridges []int
+	cuts    []int
+	blocks  [][]int
+}
+
+// newLowlink runs the lowlink dfs, where the parent edge, rather than the
+// parent vertex, is skipped, so parallel edges are handled.
+func newLowlink(g *Graph) *lowlink {
+	n := g.n
+	l := &lowlink{}
+	tin := make([]int, n)
+	low := make([]int, n)
+	it := make([]int, n)
+	pe := make([]int, n) // pe[u] is the tree edge into u, or -1.
+	cut := make([]bool, n)
+	for u := range tin {
+		tin[u] = -1
+	}
+	var path, estack []int
+	t := 0
+	for r := 0; r < n; r++ {
+		if tin[r] != -1 {
+			continue
+		}
+		tin[r], low[r], it[r], pe[r] = t, t, g.start[r], -1
+		t++
+		children := 0
+		path = append(path[:0], r)
+		for len(path) > 0 {
+			u := path[len(path)-1]
+			if it[u] < g.start[u+1] {
+				a := it[u]
+				it[u]++
+				v, e := g.to[a], g.id[a]
+				if e == pe[u] || v == u {
+					continue
+				}
+				if tin[v] == -1 {
+					tin[v], low[v], it[v], pe[v] = t, t, g.start[v], e
+					t++
+					estack = append(estack, e)
+					path = append(path, v)
+					if u == r {
+						children++
+					}
+				} else if tin[v] < tin[u] {
+					estack = append(estack, e)
+					if tin[v] < low[u] {
+						low[u] = tin[v]
+					}
+				}
+				continue
+			}
+			path = path[:len(path)-1]
+			if len(path) == 0 {
+				break
+			}
+			p := path[len(path)-1]
+			if low[u] < low[p] {
+				low[p] = low[u]
+			}
+			if low[u] > tin[p] {
+				l.bridges = append(l.bridges, pe[u])
+			}
+			if low[u] >= tin[p] {
+				if p != r && !cut[p] {
+					cut[p] = true
+					l.cuts = append(l.cuts, p)
+				}
+				var b []int
+				for {
+					e := estack[len(estack)-1]
+					estack = estack[:len(estack)-1]
+					b = append(b, e)
+					if e == pe[u] {
+						break
+					}
+				}
+				l.blocks = append(l.blocks, b)
+			}
+		}
+		if children >= 2 {
+			l.cuts = append(l.cuts, r)
+		}
+	}
+	return l
+}
+
+// Bridges returns the indices of the bridges of an undirected graph, i.e. the
+// edges whose removal disconnects their ends.
+func Bridges(g *Graph) []int {
+	return newLowlink(g).bridges
+}
+
+// ArticulationPoints returns the articulation points of an undirected graph,
+// i.e. the vertices whose removal disconnects some other vertices.
+func ArticulationPoints(g *Graph) []int {
+	return newLowlink(g).cuts
+}
+
+// Biconnected returns the biconnected components of an undirected graph, as
+// the lists of edge indices, where self-loops and isolated vertices are in
+// none of them.
+func Biconnected(g *Graph) [][]int {
+	return newLowlink(g).blocks
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// components returns the number of connected components of the undirected
+// graph without the vertex x and the edge y, ignoring the vertex x.
+func components(n int, es []Edge, x, y int) int {
+	p := make([]int, n)
+	for u := range p {
+		p[u] = u
+	}
+	var find func(u int) int
+	find = func(u int) int {
+		if p[u] != u {
+			p[u] = find(p[u])
+		}
+		return p[u]
+	}
+	c := n
+	if x >= 0 {
+		c--
+	}
+	for i, e := range es {
+		if i == y || e.U == x || e.V == x {
+			continue
+		}
+		if a, b := find(e.U), find(e.V); a != b {
+			p[a] = b
+			c--
+		}
+	}
+	return c
+}
+
+func TestBridges(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(10) + 1
+		es := randomEdges(n, rand.Intn(15))
+		g := NewUndirected(n, es)
+		c := components(n, es, -1, -1)
+		var bs, cs []int
+		for i := range es {
+			if components(n, es, -1, i) > c {
+				bs = append(bs, i)
+			}
+		}
+		for u := 0; u < n; u++ {
+			if components(n, es, u, -1) > c {
+				cs = append(cs, u)
+			}
+		}
+		if got := Bridges(g); !equal(got, bs) {
+			t.Fatalf("Bridges(%v): expected %v, got %v.", es, bs, got)
+		}
+		if got := ArticulationPoints(g); !equal(got, cs) {
+			t.Fatalf("ArticulationPoints(%v): expected %v, got %v.", es, cs, got)
+		}
+	}
+}
+
+func TestBiconnected(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(10) + 1
+		es := randomEdges(n, rand.Intn(15))
+		blocks := Biconnected(NewUndirected(n, es))
+		in := make([]int, len(es))
+		for i := range in {
+			in[i] = -1
+		}
+		for b, ids := range blocks {
+			for _, i := range ids {
+				if in[i] != -1 {
+					t.Fatalf("Biconnected(%v): edge %d in 2 blocks.", es, i)
+				}
+				in[i] = b
+			}
+		}
+		for i, e := range es {
+			if (e.U == e.V) != (in[i] == -1) {
+				t.Fatalf("Biconnected(%v): edge %d in block %d.", es, i, in[i])
+			}
+		}
+		// Two edges are in the same block iff they lie on a common simple
+		// cycle, i.e. removing any vertex other than a shared end keeps the
+		// ends of one connected to the ends of the other.
+		for i, e := range es {
+			for j, f := range es {
+				if in[i] == -1 || in[j] == -1 || i >= j {
+					continue
+				}
+				same := true
+				for x := 0; x < n && same; x++ {
+					for _, a := range []int{e.U, e.V} {
+						for _, b := range []int{f.U, f.V} {
+							if a != x && b != x && !connected(n, es, x, a, b) {
+								same = false
+							}
+						}
+					}
+				}
+				if !connected(n, es, -1, e.U, f.U) {
+					same = false
+				}
+				if same != (in[i] == in[j]) {
+					t.Fatalf("Biconnected(%v): edges %d and %d expected same %v.", es, i, j, same)
+				}
+			}
+		}
+	}
+}
+
+// connected reports if a and b are connected without the vertex x.
+func connected(n int, es []Edge, x, a, b int) bool {
+	seen := make([]bool, n)
+	seen[a] = true
+	q := []int{a}
+	for len(q) > 0 {
+		u := q[0]
+		q = q[1:]
+		for _, e := range es {
+			for _, p := range [][2]int{{e.U, e.V}, {e.V, e.U}} {
+				if p[0] == u && p[1] != x && !seen[p[1]] {
+					seen[p[1]] = true
+					q = append(q, p[1])
+				}
+			}
+		}
+	}
+	return seen[b]
+}
+
+func equal(a, b []int) bool {
+	a = append([]int(nil), a...)
+	sort.Ints(a)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBridgesDeep(t *testing.T) {
+	n := 1000000
+	es := make([]Edge, n-1)
+	for i := range es {
+		es[i] = Edge{U: i, V: i + 1}
+	}
+	g := NewUndirected(n, es)
+	if b := Bridges(g); len(b) != n-1 {
+		t.Errorf("Bridges(path): expected %d, got %d.", n-1, len(b))
+	}
+	if c := ArticulationPoints(g); len(c) != n-2 {
+		t.Errorf("ArticulationPoints(path): expected %d, got %d.", n-2, len(c))
+	}
+}
@@ -0,0 +1,150 @@
+// Package graph implements a compact graph representation shared by the graph
+// algorithms, and the basic algorithms on it: strongly connected components,
+// topological sort, bridges, articulation points, biconnected components, and
+// 2-SAT. All the traversals are iterative, so deep graphs are fine.
+package graph
+
+// Edge is an edge (U, V) of weight W. Unweighted algorithms ignore W.
+type Edge struct {
+	U, V int
+	W    int64
+}
+
+// Graph is a directed or undirected graph of vertices [0, n) in compressed
+// sparse row form, i.e. the arcs from each vertex are stored contiguously.
+// Each edge of an undirected graph is stored as two arcs.
+type Graph struct {
+	n        int
+	directed bool
+	es       []Edge
+	start    []int // The arcs from u are [start[u], start[u+1]).
+	to       []int // to[a] is the head of arc a.
+	id       []int // id[a] is the edge index of arc a.
+}
+
+// New returns a directed graph of n vertices and the edges.
+func New(n int, es []Edge) *Graph {
+	return build(n, es, true)
+}
+
+// NewUndirected returns an undirected graph of n vertices and the edges.
+func NewUndirected(n int, es []Edge) *Graph {
+	return build(n, es, false)
+}
+
+// FromAdj returns a directed graph given the adjacency lists, e.g. the ones
+// for lca.Tree or blossom, with an edge of weight 1 for each entry of the
+// lists.
+func FromAdj(adj [][]int) *Graph {
+	var es []Edge
+	for u := range adj {
+		for _, v := range adj[u] {
+			es = append(es, Edge{U: u, V: v, W: 1})
+		}
+	}
+	return New(len(adj), es)
+}
+
+// FromWeightedAdj is FromAdj where wt[u][i] is the weight of the edge (u,
+// adj[u][i]), e.g. given lca.Tree.Adj() and lca.Tree.Weights().
+func FromWeightedAdj(adj [][]int, wt [][]int64) *Graph {
+	var es []Edge
+	for u := range adj {
+		for i, v := range adj[u] {
+			es = append(es, Edge{U: u, V: v, W: wt[u][i]})
+		}
+	}
+	return New(len(adj), es)
+}
+
+func build(n int, es []Edge, directed bool) *Graph {
+	g := &Graph{
+		n:        n,
+		directed: directed,
+		es:       es,
+		start:    make([]int, n+1),
+	}
+	for _, e := range es {
+		g.start[e.U+1]++
+		if !directed {
+			g.start[e.V+1]++
+		}
+	}
+	for u := 0; u < n; u++ {
+		g.start[u+1] += g.start[u]
+	}
+	m := g.start[n]
+	g.to = make([]int, m)
+	g.id = make([]int, m)
+	pos := append([]int(nil), g.start[:n]...)
+	add := func(u, v, i int) {
+		g.to[pos[u]] = v
+		g.id[pos[u]] = i
+		pos[u]++
+	}
+	for i, e := range es {
+		add(e.U, e.V, i)
+		if !directed {
+			add(e.V, e.U, i)
+		}
+	}
+	return g
+}
+
+// N returns the num of vertices.
+func (g *Graph) N() int {
+	return g.n
+}
+
+// Directed reports if the graph is directed.
+func (g *Graph) Directed() bool {
+	return g.directed
+}
+
+// Edges returns the edges, which shall not be modified.
+func (g *Graph) Edges() []Edge {
+	return g.es
+}
+
+// Adj returns the heads of the arcs from u, which shall not be modified.
+func (g *Graph) Adj(u int) []int {
+	return g.to[g.start[u]:g.start[u+1]]
+}
+
+// EdgeIDs returns the edge indices of the arcs from u, in the same order as
+// Adj(u), which shall not be modified.
+func (g *Graph) EdgeIDs(u int) []int {
+	return g.id[g.start[u]:g.start[u+1]]
+}
+
+// Reverse returns the directed graph with all the edges reversed.
+func (g *Graph) Reverse() *Graph {
+	es := make([]Edge, len(g.es))
+	for i, e := range g.es {
+		es[i] = Edge{U: e.V, V: e.U, W: e.W}
+	}
+	return New(g.n, es)
+}
+
+// TopoSort returns the vertices of a directed graph in topological order, by
+// Kahn's algorithm, or ok == false if the graph has a cycle.
+func TopoSort(g *Graph) (ord []int, ok bool) {
+	deg := make([]int, g.n)
+	for _, v := range g.to {
+		deg[v]++
+	}
+	ord = make([]int, 0, g.n)
+	for u, d := range deg {
+		if d == 0 {
+			ord = append(ord, u)
+		}
+	}
+	for i := 0; i < len(ord); i++ {
+		for _, v := range g.Adj(ord[i]) {
+			if deg[v]--; deg[v] == 0 {
+				ord = append(ord, v)
+			}
+		}
+	}
+	return ord, len(ord) == g.n
+}
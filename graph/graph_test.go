@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomEdges(n, m int) []Edge {
+	es := make([]Edge, m)
+	for i := range es {
+		es[i] = Edge{rand.Intn(n), rand.Intn(n), rand.Int63n(10)}
+	}
+	return es
+}
+
+// reach returns the reachability matrix of a directed graph.
+func reach(n int, es []Edge) [][]bool {
+	r := make([][]bool, n)
+	for u := range r {
+		r[u] = make([]bool, n)
+		r[u][u] = true
+	}
+	for _, e := range es {
+		r[e.U][e.V] = true
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				r[i][j] = r[i][j] || r[i][k] && r[k][j]
+			}
+		}
+	}
+	return r
+}
+
+func TestAdj(t *testing.T) {
+	es := []Edge{{0, 1, 5}, {1, 2, 6}, {0, 2, 7}}
+	g := New(3, es)
+	if a := g.Adj(0); len(a) != 2 || a[0] != 1 || a[1] != 2 {
+		t.Errorf("Adj(0): expected [1 2], got %v.", a)
+	}
+	if ids := g.EdgeIDs(0); len(ids) != 2 || ids[0] != 0 || ids[1] != 2 {
+		t.Errorf("EdgeIDs(0): expected [0 2], got %v.", ids)
+	}
+	if a := g.Reverse().Adj(2); len(a) != 2 || a[0] != 1 || a[1] != 0 {
+		t.Errorf("Reverse().Adj(2): expected [1 0], got %v.", a)
+	}
+	u := NewUndirected(3, es)
+	if a := u.Adj(2); len(a) != 2 {
+		t.Errorf("Undirected Adj(2): expected 2 arcs, got %v.", a)
+	}
+	f := FromAdj([][]int{{1}, {0, 2}, {}})
+	if f.N() != 3 || len(f.Edges()) != 3 || !f.Directed() {
+		t.Errorf("FromAdj: expected 3 vertices and 3 arcs, got %d %d.", f.N(), len(f.Edges()))
+	}
+}
+
+func TestTopoSort(t *testing.T) {
+	for it := 0; it < 200; it++ {
+		n := rand.Intn(10) + 1
+		es := randomEdges(n, rand.Intn(20))
+		acyclic := true
+		r := reach(n, es)
+		for _, e := range es {
+			if r[e.V][e.U] {
+				acyclic = false
+			}
+		}
+		ord, ok := TopoSort(New(n, es))
+		if ok != acyclic {
+			t.Fatalf("TopoSort(%v): expected %v, got %v.", es, acyclic, ok)
+		}
+		if !ok {
+			continue
+		}
+		pos := make([]int, n)
+		for i, u := range ord {
+			pos[u] = i
+		}
+		for _, e := range es {
+			if pos[e.U] >= pos[e.V] {
+				t.Fatalf("TopoSort(%v): %v is not topological.", es, ord)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package graph
+
+// SCC returns the strongly connected components of a directed graph by
+// Tarjan's algorithm, where comp[u] is the component of u in [0, k). The
+// components are numbered in topological order of the condensation.
+func SCC(g *Graph) (comp []int, k int) {
+	n := g.n
+	comp = make([]int, n)
+	tin := make([]int, n)
+	low := make([]int, n)
+	it := make([]int, n) // it[u] is the next arc of u to visit.
+	for u := range tin {
+		tin[u] = -1
+		comp[u] = -1
+	}
+	var stack, path []int // The Tarjan stack, and the dfs path.
+	t := 0
+	for r := 0; r < n; r++ {
+		if tin[r] != -1 {
+			continue
+		}
+		tin[r], low[r], it[r] = t, t, g.start[r]
+		t++
+		stack = append(stack, r)
+		path = append(path, r)
+		for len(path) > 0 {
+			u := path[len(path)-1]
+			if it[u] < g.start[u+1] {
+				v := g.to[it[u]]
+				it[u]++
+				if tin[v] == -1 {
+					tin[v], low[v], it[v] = t, t, g.start[v]
+					t++
+					stack = append(stack, v)
+					path = append(path, v)
+				} else if comp[v] == -1 && tin[v] < low[u] {
+					low[u] = tin[v]
+				}
+				continue
+			}
+			path = path[:len(path)-1]
+			if len(path) > 0 {
+				if p := path[len(path)-1]; low[u] < low[p] {
+					low[p] = low[u]
+				}
+			}
+			if low[u] == tin[u] {
+				for {
+					v := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					comp[v] = k
+					if v == u {
+						break
+					}
+				}
+				k++
+			}
+		}
+	}
+	// Tarjan's algorithm finds the components in reverse topological order.
+	for u := range comp {
+		comp[u] = k - 1 - comp[u]
+	}
+	return comp, k
+}
+
+// Kosaraju is SCC by Kosaraju's algorithm, which gives the same numbering.
+func Kosaraju(g *Graph) (comp []int, k int) {
+	n := g.n
+	seen := make([]bool, n)
+	it := make([]int, n)
+	ord := make([]int, 0, n) // The vertices by finishing time.
+	var path []int
+	for r := 0; r < n; r++ {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		it[r] = g.start[r]
+		path = append(path, r)
+		for len(path) > 0 {
+			u := path[len(path)-1]
+			if it[u] < g.start[u+1] {
+				v := g.to[it[u]]
+				it[u]++
+				if !seen[v] {
+					seen[v] = true
+					it[v] = g.start[v]
+					path = append(path, v)
+				}
+				continue
+			}
+			path = path[:len(path)-1]
+			ord = append(ord, u)
+		}
+	}
+
+	rg := g.Reverse()
+	comp = make([]int, n)
+	for u := range comp {
+		comp[u] = -1
+	}
+	for i := n - 1; i >= 0; i-- {
+		r := ord[i]
+		if comp[r] != -1 {
+			continue
+		}
+		comp[r] = k
+		stack := []int{r}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range rg.Adj(u) {
+				if comp[v] == -1 {
+					comp[v] = k
+					stack = append(stack, v)
+				}
+			}
+		}
+		k++
+	}
+	return comp, k
+}
+
+// Condense returns the condensation of a directed graph given its strongly
+// connected components, i.e. the DAG of the k components, with an edge for
+// each pair of components joined by some edges, weighted by the min weight.
+func Condense(g *Graph, comp []int, k int) *Graph {
+	type pair struct{ u, v int }
+	idx := make(map[pair]int)
+	var es []Edge
+	for _, e := range g.es {
+		u, v := comp[e.U], comp[e.V]
+		if u == v {
+			continue
+		}
+		if i, ok := idx[pair{u, v}]; !ok {
+			idx[pair{u, v}] = len(es)
+			es = append(es, Edge{U: u, V: v, W: e.W})
+		} else if e.W < es[i].W {
+			es[i].W = e.W
+		}
+	}
+	return New(k, es)
+}
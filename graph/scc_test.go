@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSCC(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(12) + 1
+		es := randomEdges(n, rand.Intn(30))
+		r := reach(n, es)
+		g := New(n, es)
+		for name, f := range map[string]func(*Graph) ([]int, int){
+			"SCC":      SCC,
+			"Kosaraju": Kosaraju,
+		} {
+			comp, k := f(g)
+			for u := 0; u < n; u++ {
+				if comp[u] < 0 || comp[u] >= k {
+					t.Fatalf("%s: component %d out of [0, %d).", name, comp[u], k)
+				}
+				for v := 0; v < n; v++ {
+					if same := r[u][v] && r[v][u]; same != (comp[u] == comp[v]) {
+						t.Fatalf("%s(%v): %d and %d expected same %v.", name, es, u, v, same)
+					}
+				}
+			}
+			for _, e := range es {
+				if comp[e.U] > comp[e.V] {
+					t.Fatalf("%s(%v): components not in topological order: %v.", name, es, comp)
+				}
+			}
+			c := Condense(g, comp, k)
+			if _, ok := TopoSort(c); !ok || c.N() != k {
+				t.Fatalf("Condense(%v): expected a dag of %d vertices.", es, k)
+			}
+			for _, e := range c.Edges() {
+				if e.U == e.V {
+					t.Fatalf("Condense(%v): self-loop %v.", es, e)
+				}
+			}
+		}
+	}
+}
+
+func TestSCCDeep(t *testing.T) {
+	n := 1000000
+	es := make([]Edge, n)
+	for i := range es {
+		es[i] = Edge{U: i, V: (i + 1) % n}
+	}
+	if _, k := SCC(New(n, es)); k != 1 {
+		t.Errorf("SCC(cycle): expected 1, got %d.", k)
+	}
+	if _, k := Kosaraju(New(n, es[:n-1])); k != n {
+		t.Errorf("Kosaraju(path): expected %d, got %d.", n, k)
+	}
+}
@@ -0,0 +1,43 @@
+package graph
+
+// TwoSAT is a 2-satisfiability problem of n boolean variables.
+type TwoSAT struct {
+	n  int
+	es []Edge
+}
+
+// NewTwoSAT returns a 2-SAT problem of n variables and no clauses.
+func NewTwoSAT(n int) *TwoSAT {
+	return &TwoSAT{n: n}
+}
+
+// lit returns the vertex of the literal x == v in the implication graph.
+func (s *TwoSAT) lit(x int, v bool) int {
+	if v {
+		return 2 * x
+	}
+	return 2*x + 1
+}
+
+// AddClause adds the clause (x == vx) || (y == vy).
+func (s *TwoSAT) AddClause(x int, vx bool, y int, vy bool) {
+	s.es = append(s.es,
+		Edge{U: s.lit(x, !vx), V: s.lit(y, vy)},
+		Edge{U: s.lit(y, !vy), V: s.lit(x, vx)})
+}
+
+// Solve returns a satisfying assignment, or ok == false if none. A variable is
+// true iff its true literal comes later in the topological order of the
+// implication graph's components than its false literal.
+func (s *TwoSAT) Solve() (as []bool, ok bool) {
+	comp, _ := SCC(New(2*s.n, s.es))
+	as = make([]bool, s.n)
+	for x := range as {
+		t, f := comp[s.lit(x, true)], comp[s.lit(x, false)]
+		if t == f {
+			return nil, false
+		}
+		as[x] = t > f
+	}
+	return as, true
+}
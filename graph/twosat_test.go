@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type clause struct {
+	x, y   int
+	vx, vy bool
+}
+
+func TestTwoSAT(t *testing.T) {
+	for it := 0; it < 500; it++ {
+		n := rand.Intn(8) + 1
+		cs := make([]clause, rand.Intn(3*n))
+		s := NewTwoSAT(n)
+		for i := range cs {
+			c := clause{rand.Intn(n), rand.Intn(n), rand.Intn(2) == 0, rand.Intn(2) == 0}
+			cs[i] = c
+			s.AddClause(c.x, c.vx, c.y, c.vy)
+		}
+		sat := func(as []bool) bool {
+			for _, c := range cs {
+				if as[c.x] != c.vx && as[c.y] != c.vy {
+					return false
+				}
+			}
+			return true
+		}
+		expected := false
+		for mask := 0; mask < 1<<uint(n) && !expected; mask++ {
+			as := make([]bool, n)
+			for x := range as {
+				as[x] = mask>>uint(x)&1 == 1
+			}
+			expected = sat(as)
+		}
+		as, ok := s.Solve()
+		if ok != expected {
+			t.Fatalf("Solve(%v): expected %v, got %v.", cs, expected, ok)
+		}
+		if ok && !sat(as) {
+			t.Fatalf("Solve(%v): %v is not satisfying.", cs, as)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package lca
+
+import "math/bits"
+
+// EulerTree supports online lca queries in O(1) time, by a sparse table of
+// range minimum depths over the dfs order.
+type EulerTree struct {
+	forest
+	sp [][]int32 // sp[d][i] is the shallowest node in ord[i : i+2^d].
+}
+
+// NewEulerTree returns an n-singleton florest.
+func NewEulerTree(n int) *EulerTree {
+	return &EulerTree{forest: newForest(n)}
+}
+
+// Build finalizes the forest, and pre-computes for lca queries. Each tree is
+// rooted at the first of roots it contains, or at its smallest node if it
+// contains none of them.
+func (t *EulerTree) Build(roots ...int) {
+	t.build(roots)
+	s := make([]int32, t.n)
+	for i, u := range t.ord {
+		s[i] = int32(u)
+	}
+	t.sp = [][]int32{s}
+	for d := 1; 1<<uint(d) <= t.n; d++ {
+		p := t.sp[d-1]
+		h := 1 << uint(d-1)
+		q := make([]int32, t.n-2*h+1)
+		for i := range q {
+			q[i] = t.min(p[i], p[i+h])
+		}
+		t.sp = append(t.sp, q)
+	}
+}
+
+func (t *EulerTree) min(x, y int32) int32 {
+	if t.dep[y] < t.dep[x] {
+		return y
+	}
+	return x
+}
+
+// Lca returns the lca of u, v, and ok == false if they are in different trees.
+func (t *EulerTree) Lca(u, v int) (w int, ok bool) {
+	if !t.SameTree(u, v) {
+		return -1, false
+	}
+	return t.lca(u, v), true
+}
+
+// lca finds the shallowest node z in the dfs order after u up to v, whose
+// parent is the lca, since the path from u to v leaves the lca through z.
+func (t *EulerTree) lca(u, v int) int {
+	if u == v {
+		return u
+	}
+	a, b := t.tin[u], t.tin[v]
+	if a > b {
+		a, b = b, a
+	}
+	a++
+	d := bits.Len(uint(b-a+1)) - 1
+	z := t.min(t.sp[d][a], t.sp[d][b-(1<<uint(d))+1])
+	return t.pnt[z]
+}
+
+// Dist returns the distant from u to v, or -1 if they are in different trees.
+func (t *EulerTree) Dist(u, v int) int {
+	if !t.SameTree(u, v) {
+		return -1
+	}
+	return t.dist(u, v, t.lca(u, v))
+}
+
+// WeightedDist returns the total weight of the path from u to v, or -1 if
+// they are in different trees.
+func (t *EulerTree) WeightedDist(u, v int) int64 {
+	if !t.SameTree(u, v) {
+		return -1
+	}
+	return t.weightedDist(u, v, t.lca(u, v))
+}
+
+// VirtualTree returns the virtual tree of the key nodes.
+func (t *EulerTree) VirtualTree(keys []int) *VirtualTree {
+	return t.virtualTree(keys, t.lca)
+}
@@ -1,21 +1,245 @@
-// Package lca supports online least common ancestor queries on a tree.
+// Package lca supports least common ancestor queries on a forest.
+//
+// All the backends are built the same way: Link the edges, then Build. Edges
+// added by Link have weight 1. Tree answers queries online with binary
+// lifting, in O(log n) time each; EulerTree answers them online with a sparse
+// table over the dfs order, in O(1) time each; LcaBatch answers a batch of
+// queries offline with Tarjan's algorithm, in nearly linear time.
 package lca
 
-import (
-	"unsafe"
-)
+import "sort"
 
-// Tree is where the lca queries on.
-type Tree struct {
-	n  int
-	lg int
-	ns []node
+// LCA is the interface shared by the lca backends.
+type LCA interface {
+	// Link adds an undirected edge (u, v).
+	Link(u, v int)
+	// LinkWeighted adds an undirected edge (u, v) of weight w.
+	LinkWeighted(u, v int, w int64)
+	// Build finalizes the forest, and pre-computes for lca queries.
+	Build(roots ...int)
+	// SameTree reports if u and v are in the same tree.
+	SameTree(u, v int) bool
+	// Root returns the root of the tree containing u.
+	Root(u int) int
+	// Lca returns the lca of u, v, and ok == false if they are in different
+	// trees.
+	Lca(u, v int) (w int, ok bool)
+	// Dist returns the distant from u to v, or -1 if they are in different
+	// trees.
+	Dist(u, v int) int
+	// WeightedDist returns the total weight of the path from u to v, or -1 if
+	// they are in different trees.
+	WeightedDist(u, v int) int64
+	// LcaBatch returns the lca of each pair, or -1 for pairs in different
+	// trees.
+	LcaBatch(ps []Pair) []int
+	// VirtualTree returns the virtual tree of the key nodes.
+	VirtualTree(keys []int) *VirtualTree
 }
 
-type node struct {
-	dep int
-	pnt []*node
-	adj []*node
+// Pair is a pair of nodes to query.
+type Pair struct {
+	U, V int
+}
+
+// forest holds the edges and the dfs order shared by the backends.
+type forest struct {
+	n    int
+	adj  [][]int
+	wt   [][]int64 // wt[u][i] is the weight of edge (u, adj[u][i]).
+	pnt  []int     // pnt[r] == r for a root r.
+	pw   []int64   // pw[u] is the weight of edge (u, pnt[u]).
+	dep  []int
+	wdep []int64 // The weighted depth.
+	root []int
+	ord  []int // The dfs preorder.
+	tin  []int // tin[u] is the index of u in ord.
+	tout []int // ord[tin[u]:tout[u]] is the subtree of u.
+
+	weighted bool // Whether any edge has a weight other than 1.
+}
+
+func newForest(n int) forest {
+	f := forest{
+		n:    n,
+		adj:  make([][]int, n),
+		wt:   make([][]int64, n),
+		pnt:  make([]int, n),
+		pw:   make([]int64, n),
+		dep:  make([]int, n),
+		wdep: make([]int64, n),
+		root: make([]int, n),
+		ord:  make([]int, 0, n),
+		tin:  make([]int, n),
+		tout: make([]int, n),
+	}
+	for i := range f.dep {
+		f.dep[i] = -1
+	}
+	return f
+}
+
+// Link adds an undirected edge (u, v).
+func (f *forest) Link(u, v int) {
+	f.LinkWeighted(u, v, 1)
+}
+
+// LinkWeighted adds an undirected edge (u, v) of weight w.
+func (f *forest) LinkWeighted(u, v int, w int64) {
+	f.adj[u] = append(f.adj[u], v)
+	f.adj[v] = append(f.adj[v], u)
+	f.wt[u] = append(f.wt[u], w)
+	f.wt[v] = append(f.wt[v], w)
+	if w != 1 {
+		f.weighted = true
+	}
+}
+
+// Adj returns the adjacency lists of the forest, which shall not be modified.
+// Other tree packages, e.g. centroid, take them as the input.
+func (f *forest) Adj() [][]int {
+	return f.adj
+}
+
+// Weights returns the weights of the edges in the same order as Adj(), which
+// shall not be modified.
+func (f *forest) Weights() [][]int64 {
+	return f.wt
+}
+
+// build roots each tree at the first of roots it contains, or at its smallest
+// node if it contains none of them.
+func (f *forest) build(roots []int) {
+	for _, r := range roots {
+		if f.dep[r] == -1 {
+			f.dfs(r)
+		}
+	}
+	for r := 0; r < f.n; r++ {
+		if f.dep[r] == -1 {
+			f.dfs(r)
+		}
+	}
+	for i, u := range f.ord {
+		f.tin[u] = i
+		f.tout[u] = i + 1
+	}
+	for i := f.n - 1; i >= 0; i-- {
+		if u := f.ord[i]; f.pnt[u] != u && f.tout[f.pnt[u]] < f.tout[u] {
+			f.tout[f.pnt[u]] = f.tout[u]
+		}
+	}
+}
+
+func (f *forest) dfs(r int) {
+	f.pnt[r] = r
+	f.root[r] = r
+	f.dep[r] = 0
+	stack := []int{r}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f.ord = append(f.ord, u)
+		for i, v := range f.adj[u] {
+			if f.dep[v] == -1 {
+				f.pnt[v] = u
+				f.pw[v] = f.wt[u][i]
+				f.root[v] = r
+				f.dep[v] = f.dep[u] + 1
+				f.wdep[v] = f.wdep[u] + f.wt[u][i]
+				stack = append(stack, v)
+			}
+		}
+	}
+}
+
+// SameTree reports if u and v are in the same tree.
+func (f *forest) SameTree(u, v int) bool {
+	return f.root[u] == f.root[v]
+}
+
+// Root returns the root of the tree containing u.
+func (f *forest) Root(u int) int {
+	return f.root[u]
+}
+
+func (f *forest) dist(u, v, w int) int {
+	return f.dep[u] + f.dep[v] - 2*f.dep[w]
+}
+
+func (f *forest) weightedDist(u, v, w int) int64 {
+	return f.wdep[u] + f.wdep[v] - 2*f.wdep[w]
+}
+
+// isAncestor reports if u is an ancestor of v, or v itself.
+func (f *forest) isAncestor(u, v int) bool {
+	return f.tin[u] <= f.tin[v] && f.tin[v] < f.tout[u]
+}
+
+// VirtualTree is the virtual tree, a.k.a. the auxiliary tree, of a set of key
+// nodes. It consists of the key nodes and the lcas of them, and keeps the
+// ancestor relations among them. A forest of key nodes gives a forest.
+type VirtualTree struct {
+	Nodes  []int // The nodes, in dfs order.
+	Parent []int // Parent[i] is the index of the parent of Nodes[i], or -1.
+	Len    []int // Len[i] is the distance from Nodes[i] to its parent, or 0.
+}
+
+// virtualTree builds the virtual tree of keys in O(k log k) time and lca
+// queries, using the fact that the lcas of the dfs-order-adjacent keys are
+// all the lcas needed.
+func (f *forest) virtualTree(keys []int, lca func(u, v int) int) *VirtualTree {
+	vs := append([]int(nil), keys...)
+	byTin := func(vs []int) []int {
+		sort.Slice(vs, func(i, j int) bool { return f.tin[vs[i]] < f.tin[vs[j]] })
+		k := 0
+		for i, u := range vs {
+			if i == 0 || u != vs[k-1] {
+				vs[k] = u
+				k++
+			}
+		}
+		return vs[:k]
+	}
+	vs = byTin(vs)
+	for i, k := 1, len(vs); i < k; i++ {
+		if f.SameTree(vs[i-1], vs[i]) {
+			vs = append(vs, lca(vs[i-1], vs[i]))
+		}
+	}
+	vs = byTin(vs)
+
+	vt := &VirtualTree{
+		Nodes:  vs,
+		Parent: make([]int, len(vs)),
+		Len:    make([]int, len(vs)),
+	}
+	var stack []int
+	for i, u := range vs {
+		for len(stack) > 0 && !f.isAncestor(vs[stack[len(stack)-1]], u) {
+			stack = stack[:len(stack)-1]
+		}
+		vt.Parent[i] = -1
+		if len(stack) > 0 {
+			j := stack[len(stack)-1]
+			vt.Parent[i] = j
+			vt.Len[i] = f.dep[u] - f.dep[vs[j]]
+		}
+		stack = append(stack, i)
+	}
+	return vt
+}
+
+// Tree supports online lca queries by binary lifting. Besides, it supports
+// k-th ancestor queries, and max/min edge weight queries on paths.
+type Tree struct {
+	forest
+	lg int
+	up [][]int // up[d][u] is the 2^d-th ancestor of u.
+	// mx[d][u] and mn[d][u] are the max and min edge weights from u up to
+	// up[d][u]. They are nil if all the edge weights are 1.
+	mx [][]int64
+	mn [][]int64
 }
 
 // NewTree returns an n-singleton florest.
@@ -24,84 +248,172 @@ func NewTree(n int) *Tree {
 	for d := 1; d < n; d <<= 1 {
 		lg++
 	}
-	t := &Tree{
-		n:  n,
-		lg: lg,
-		ns: make([]node, n),
+	return &Tree{
+		forest: newForest(n),
+		lg:     lg,
+	}
+}
+
+// Build finalizes the forest, and pre-computes for lca queries. Each tree is
+// rooted at the first of roots it contains, or at its smallest node if it
+// contains none of them.
+func (t *Tree) Build(roots ...int) {
+	t.build(roots)
+	t.up = make([][]int, t.lg)
+	t.up[0] = t.pnt
+	for d := 1; d < t.lg; d++ {
+		p, q := t.up[d-1], make([]int, t.n)
+		for u := range q {
+			q[u] = p[p[u]]
+		}
+		t.up[d] = q
+	}
+	t.mx, t.mn = nil, nil
+	if !t.weighted {
+		return
 	}
-	alloc := make([]*node, lg*n)
-	for i := range t.ns {
-		x := &t.ns[i]
-		x.dep = -1
-		x.pnt = alloc[i*lg : (i+1)*lg]
+	t.mx = make([][]int64, t.lg)
+	t.mn = make([][]int64, t.lg)
+	t.mx[0] = t.pw
+	t.mn[0] = t.pw
+	for d := 1; d < t.lg; d++ {
+		p := t.up[d-1]
+		mx, mn := make([]int64, t.n), make([]int64, t.n)
+		for u := range mx {
+			mx[u] = max64(t.mx[d-1][u], t.mx[d-1][p[u]])
+			mn[u] = min64(t.mn[d-1][u], t.mn[d-1][p[u]])
+		}
+		t.mx[d] = mx
+		t.mn[d] = mn
 	}
-	return t
 }
 
-// Link adds an undirected edge (u, v).
-func (t *Tree) Link(u, v int) {
-	x := &t.ns[u]
-	y := &t.ns[v]
-	x.adj = append(x.adj, y)
-	y.adj = append(y.adj, x)
+// Lca returns the lca of u, v, and ok == false if they are in different trees.
+func (t *Tree) Lca(u, v int) (w int, ok bool) {
+	if !t.SameTree(u, v) {
+		return -1, false
+	}
+	return t.lca(u, v), true
 }
 
-// Build finalizes the tree, and pre-computes for lca queries.
-func (t *Tree) Build() {
-	t.build(&t.ns[0], &t.ns[0], 0)
-	for d := 1; d < t.lg; d++ {
-		for i := range t.ns {
-			x := &t.ns[i]
-			x.pnt[d] = x.pnt[d-1].pnt[d-1]
+func (t *Tree) lca(u, v int) int {
+	if t.dep[u] < t.dep[v] {
+		u, v = v, u
+	}
+	u = t.ancestor(u, t.dep[u]-t.dep[v])
+	for i := t.lg - 1; i >= 0; i-- {
+		u1 := t.up[i][u]
+		v1 := t.up[i][v]
+		if u1 != v1 {
+			u, v = u1, v1
 		}
 	}
+	if u != v {
+		u = t.pnt[u]
+	}
+	return u
 }
 
-func (t *Tree) build(u, p *node, d int) {
-	u.pnt[0] = p
-	u.dep = d
-	for _, v := range u.adj {
-		if v.dep == -1 {
-			t.build(v, u, d+1)
+// Dist returns the distant from u to v, or -1 if they are in different trees.
+func (t *Tree) Dist(u, v int) int {
+	if !t.SameTree(u, v) {
+		return -1
+	}
+	return t.dist(u, v, t.lca(u, v))
+}
+
+// WeightedDist returns the total weight of the path from u to v, or -1 if
+// they are in different trees.
+func (t *Tree) WeightedDist(u, v int) int64 {
+	if !t.SameTree(u, v) {
+		return -1
+	}
+	return t.weightedDist(u, v, t.lca(u, v))
+}
+
+// VirtualTree returns the virtual tree of the key nodes.
+func (t *Tree) VirtualTree(keys []int) *VirtualTree {
+	return t.virtualTree(keys, t.lca)
+}
+
+func (t *Tree) ancestor(u, k int) int {
+	for i := 0; k > 0; i, k = i+1, k>>1 {
+		if k&1 != 0 {
+			u = t.up[i][u]
 		}
 	}
+	return u
 }
 
-// Lca returns the lca of u, v.
-func (t *Tree) Lca(u, v int) int {
-	x := &t.ns[u]
-	y := &t.ns[v]
-	z := t.lca(x, y)
-	return int((uintptr(unsafe.Pointer(z)) - uintptr(unsafe.Pointer(&t.ns[0]))) / unsafe.Sizeof(t.ns[0]))
+// KthAncestor returns the k-th ancestor of u, or -1 if k is greater than the
+// depth of u. The 0-th ancestor of u is u itself.
+func (t *Tree) KthAncestor(u, k int) int {
+	if k < 0 || k > t.dep[u] {
+		return -1
+	}
+	return t.ancestor(u, k)
 }
 
-func (t *Tree) lca(x, y *node) *node {
-	if x.dep < y.dep {
-		x, y = y, x
+// KthOnPath returns the k-th node on the path from u to v, or -1 if k is
+// greater than Dist(u, v). The 0-th node is u itself.
+func (t *Tree) KthOnPath(u, v, k int) int {
+	if !t.SameTree(u, v) || k < 0 {
+		return -1
 	}
-	d := x.dep - y.dep
-	for i := 0; i < t.lg; i++ {
-		if d&(1<<uint(i)) > 0 {
-			x = x.pnt[i]
-		}
+	w := t.lca(u, v)
+	if du := t.dep[u] - t.dep[w]; k <= du {
+		return t.ancestor(u, k)
+	} else if dv := t.dep[v] - t.dep[w]; k <= du+dv {
+		return t.ancestor(v, du+dv-k)
 	}
-	for i := t.lg - 1; i >= 0; i-- {
-		x1 := x.pnt[i]
-		y1 := y.pnt[i]
-		if x1 != y1 {
-			x, y = x1, y1
+	return -1
+}
+
+// PathMax returns the max edge weight on the path from u to v, and
+// ok == false if the path has no edges.
+func (t *Tree) PathMax(u, v int) (w int64, ok bool) {
+	return t.aggregate(u, v, t.mx, max64)
+}
+
+// PathMin returns the min edge weight on the path from u to v, and
+// ok == false if the path has no edges.
+func (t *Tree) PathMin(u, v int) (w int64, ok bool) {
+	return t.aggregate(u, v, t.mn, min64)
+}
+
+func (t *Tree) aggregate(u, v int, tb [][]int64, f func(x, y int64) int64) (w int64, ok bool) {
+	if u == v || !t.SameTree(u, v) {
+		return 0, false
+	}
+	if tb == nil {
+		return 1, true
+	}
+	z := t.lca(u, v)
+	for _, x := range []int{u, v} {
+		for i, k := 0, t.dep[x]-t.dep[z]; k > 0; i, k = i+1, k>>1 {
+			if k&1 != 0 {
+				if ok {
+					w = f(w, tb[i][x])
+				} else {
+					w, ok = tb[i][x], true
+				}
+				x = t.up[i][x]
+			}
 		}
 	}
-	if x != y {
-		x = x.pnt[0]
+	return w, ok
+}
+
+func max64(x, y int64) int64 {
+	if x > y {
+		return x
 	}
-	return x
+	return y
 }
 
-// Dist returns the distant from u to v.
-func (t *Tree) Dist(u, v int) int {
-	x := &t.ns[u]
-	y := &t.ns[v]
-	z := t.lca(x, y)
-	return x.dep + y.dep - 2*z.dep
+func min64(x, y int64) int64 {
+	if x < y {
+		return x
+	}
+	return y
 }
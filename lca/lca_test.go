@@ -1,39 +1,321 @@
 package lca
 
 import (
+	"math/rand"
 	"testing"
 )
 
+func backends(n int) map[string]LCA {
+	return map[string]LCA{
+		"Tree":      NewTree(n),
+		"EulerTree": NewEulerTree(n),
+	}
+}
+
 func TestLca(t *testing.T) {
-	tree := NewTree(10)
-	tree.Link(3, 9)
-	tree.Link(9, 5)
-	tree.Link(1, 8)
-	tree.Link(8, 5)
-	tree.Link(7, 4)
-	tree.Link(6, 0)
-	tree.Link(3, 6)
-	tree.Link(6, 2)
-	tree.Link(0, 7)
+	for name, tree := range backends(10) {
+		tree.Link(3, 9)
+		tree.Link(9, 5)
+		tree.Link(1, 8)
+		tree.Link(8, 5)
+		tree.Link(7, 4)
+		tree.Link(6, 0)
+		tree.Link(3, 6)
+		tree.Link(6, 2)
+		tree.Link(0, 7)
+		tree.Build()
+		testLca := func(u, v, e int) {
+			if g, ok := tree.Lca(u, v); !ok || g != e {
+				t.Errorf("%s.Lca(%d, %d): expected %d, got %d.", name, u, v, e, g)
+			}
+		}
+		testDist := func(u, v, e int) {
+			if g := tree.Dist(u, v); g != e {
+				t.Errorf("%s.Dist(%d, %d): expected %d, got %d.", name, u, v, e, g)
+			}
+		}
+		testLca(4, 2, 0)
+		testLca(9, 9, 9)
+		testLca(1, 0, 0)
+		testLca(3, 2, 6)
+		testDist(2, 3, 2)
+		testDist(1, 1, 0)
+		testDist(4, 1, 8)
+		testDist(5, 9, 1)
+		testDist(2, 4, 4)
+		testDist(9, 7, 4)
+	}
+}
+
+func TestForest(t *testing.T) {
+	for name, tree := range backends(9) {
+		tree.Link(0, 1)
+		tree.Link(1, 2)
+		tree.Link(1, 3)
+		tree.Link(4, 5)
+		tree.Link(5, 6)
+		tree.Link(5, 7)
+		tree.Build(5)
+		testLca := func(u, v, e int) {
+			g, ok := tree.Lca(u, v)
+			if ok != (e != -1) || g != e {
+				t.Errorf("%s.Lca(%d, %d): expected %d, got %d, %v.", name, u, v, e, g, ok)
+			}
+			if tree.SameTree(u, v) != ok {
+				t.Errorf("%s.SameTree(%d, %d): expected %v.", name, u, v, ok)
+			}
+		}
+		testLca(2, 3, 1)
+		testLca(0, 3, 0)
+		testLca(4, 6, 5)
+		testLca(6, 7, 5)
+		testLca(8, 8, 8)
+		testLca(2, 6, -1)
+		testLca(3, 8, -1)
+		for u, e := range []int{0, 0, 0, 0, 5, 5, 5, 5, 8} {
+			if g := tree.Root(u); g != e {
+				t.Errorf("%s.Root(%d): expected %d, got %d.", name, u, e, g)
+			}
+		}
+		if g := tree.Dist(4, 7); g != 2 {
+			t.Errorf("%s.Dist(4, 7): expected 2, got %d.", name, g)
+		}
+		if g := tree.Dist(0, 4); g != -1 {
+			t.Errorf("%s.Dist(0, 4): expected -1, got %d.", name, g)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	const n = 300
+	pnt := make([]int, n)
+	bs := backends(n)
+	for u := range pnt {
+		pnt[u] = -1
+		if u > 0 && rand.Intn(20) > 0 {
+			pnt[u] = rand.Intn(u)
+			for _, tree := range bs {
+				tree.Link(u, pnt[u])
+			}
+		}
+	}
+	naive := func(u, v int) int {
+		seen := make(map[int]bool)
+		for x := u; x != -1; x = pnt[x] {
+			seen[x] = true
+		}
+		for y := v; y != -1; y = pnt[y] {
+			if seen[y] {
+				return y
+			}
+		}
+		return -1
+	}
+	ps := make([]Pair, 1000)
+	for i := range ps {
+		ps[i] = Pair{rand.Intn(n), rand.Intn(n)}
+	}
+	for name, tree := range bs {
+		tree.Build()
+		res := tree.LcaBatch(ps)
+		for i, p := range ps {
+			e := naive(p.U, p.V)
+			if g, _ := tree.Lca(p.U, p.V); g != e {
+				t.Errorf("%s.Lca(%d, %d): expected %d, got %d.", name, p.U, p.V, e, g)
+			}
+			if res[i] != e {
+				t.Errorf("%s.LcaBatch: pair (%d, %d): expected %d, got %d.", name, p.U, p.V, e, res[i])
+			}
+		}
+	}
+}
+
+func BenchmarkTreeLca(b *testing.B) {
+	benchmarkLca(b, NewTree(1<<16))
+}
+
+func BenchmarkEulerTreeLca(b *testing.B) {
+	benchmarkLca(b, NewEulerTree(1<<16))
+}
+
+func benchmarkLca(b *testing.B, tree LCA) {
+	const n = 1 << 16
+	for u := 1; u < n; u++ {
+		tree.Link(u, rand.Intn(u))
+	}
 	tree.Build()
-	testLca := func(u, v, e int) {
-		if g := tree.Lca(u, v); g != e {
-			t.Errorf("Lca(%d, %d): expected %d, got %d.", u, v, e, g)
-		}
-	}
-	testDist := func(u, v, e int) {
-		if g := tree.Dist(u, v); g != e {
-			t.Errorf("Dist(%d, %d): expected %d, got %d.", u, v, e, g)
-		}
-	}
-	testLca(4, 2, 0)
-	testLca(9, 9, 9)
-	testLca(1, 0, 0)
-	testLca(3, 2, 6)
-	testDist(2, 3, 2)
-	testDist(1, 1, 0)
-	testDist(4, 1, 8)
-	testDist(5, 9, 1)
-	testDist(2, 4, 4)
-	testDist(9, 7, 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Lca(rand.Intn(n), rand.Intn(n))
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	// 0 -5- 1 -2- 2 -7- 3
+	//       |
+	//       9- 4 -1- 5
+	tree := NewTree(7)
+	tree.LinkWeighted(0, 1, 5)
+	tree.LinkWeighted(1, 2, 2)
+	tree.LinkWeighted(2, 3, 7)
+	tree.LinkWeighted(1, 4, 9)
+	tree.LinkWeighted(4, 5, 1)
+	tree.Build()
+	testDist := func(u, v int, e int64) {
+		if g := tree.WeightedDist(u, v); g != e {
+			t.Errorf("WeightedDist(%d, %d): expected %d, got %d.", u, v, e, g)
+		}
+	}
+	testDist(3, 5, 19)
+	testDist(0, 3, 14)
+	testDist(4, 4, 0)
+	testDist(0, 6, -1)
+	testAnc := func(u, k, e int) {
+		if g := tree.KthAncestor(u, k); g != e {
+			t.Errorf("KthAncestor(%d, %d): expected %d, got %d.", u, k, e, g)
+		}
+	}
+	testAnc(3, 0, 3)
+	testAnc(3, 2, 1)
+	testAnc(5, 3, 0)
+	testAnc(5, 4, -1)
+	testPath := func(u, v, k, e int) {
+		if g := tree.KthOnPath(u, v, k); g != e {
+			t.Errorf("KthOnPath(%d, %d, %d): expected %d, got %d.", u, v, k, e, g)
+		}
+	}
+	testPath(3, 5, 0, 3)
+	testPath(3, 5, 2, 1)
+	testPath(3, 5, 3, 4)
+	testPath(3, 5, 4, 5)
+	testPath(3, 5, 5, -1)
+	testPath(0, 6, 0, -1)
+	testAgg := func(u, v int, emx, emn int64) {
+		mx, ok1 := tree.PathMax(u, v)
+		mn, ok2 := tree.PathMin(u, v)
+		if !ok1 || !ok2 || mx != emx || mn != emn {
+			t.Errorf("PathMax/PathMin(%d, %d): expected %d/%d, got %d/%d.", u, v, emx, emn, mx, mn)
+		}
+	}
+	testAgg(3, 5, 9, 1)
+	testAgg(0, 2, 5, 2)
+	testAgg(2, 3, 7, 7)
+	if _, ok := tree.PathMax(2, 2); ok {
+		t.Errorf("PathMax(2, 2): expected no edges.")
+	}
+}
+
+func TestUnweighted(t *testing.T) {
+	tree := NewTree(4)
+	tree.Link(0, 1)
+	tree.Link(1, 2)
+	tree.Build()
+	if tree.mx != nil || tree.mn != nil {
+		t.Errorf("Build: expected no max/min tables for an unweighted tree.")
+	}
+	mx, ok1 := tree.PathMax(0, 2)
+	mn, ok2 := tree.PathMin(2, 0)
+	if !ok1 || !ok2 || mx != 1 || mn != 1 {
+		t.Errorf("PathMax/PathMin(0, 2): expected 1/1, got %d/%d.", mx, mn)
+	}
+	if _, ok := tree.PathMax(0, 3); ok {
+		t.Errorf("PathMax(0, 3): expected no path.")
+	}
+}
+
+func TestRandomWeighted(t *testing.T) {
+	const n = 200
+	pnt := make([]int, n)
+	pw := make([]int64, n)
+	tree := NewTree(n)
+	for u := 1; u < n; u++ {
+		pnt[u] = rand.Intn(u)
+		pw[u] = rand.Int63n(1000)
+		tree.LinkWeighted(u, pnt[u], pw[u])
+	}
+	tree.Build()
+	for i := 0; i < 1000; i++ {
+		u, v := rand.Intn(n), rand.Intn(n)
+		w, _ := tree.Lca(u, v)
+		var path []int
+		var sum, mx int64
+		for x := u; x != w; x = pnt[x] {
+			path = append(path, x)
+			sum += pw[x]
+			mx = max64(mx, pw[x])
+		}
+		path = append(path, w)
+		k := len(path)
+		for y := v; y != w; y = pnt[y] {
+			path = append(path, y)
+			sum += pw[y]
+			mx = max64(mx, pw[y])
+		}
+		for a, b := k, len(path)-1; a < b; a, b = a+1, b-1 {
+			path[a], path[b] = path[b], path[a]
+		}
+		if g := tree.WeightedDist(u, v); g != sum {
+			t.Errorf("WeightedDist(%d, %d): expected %d, got %d.", u, v, sum, g)
+		}
+		if g, _ := tree.PathMax(u, v); g != mx {
+			t.Errorf("PathMax(%d, %d): expected %d, got %d.", u, v, mx, g)
+		}
+		for k, e := range path {
+			if g := tree.KthOnPath(u, v, k); g != e {
+				t.Errorf("KthOnPath(%d, %d, %d): expected %d, got %d.", u, v, k, e, g)
+			}
+		}
+	}
+}
+
+func TestVirtualTree(t *testing.T) {
+	const n = 200
+	for name, tree := range backends(n) {
+		for u := 1; u < n; u++ {
+			if rand.Intn(10) > 0 {
+				tree.Link(u, rand.Intn(u))
+			}
+		}
+		tree.Build()
+		for i := 0; i < 100; i++ {
+			keys := make([]int, 1+rand.Intn(10))
+			for j := range keys {
+				keys[j] = rand.Intn(n)
+			}
+			vt := tree.VirtualTree(keys)
+			in := make(map[int]int)
+			for j, u := range vt.Nodes {
+				in[u] = j
+			}
+			for _, u := range keys {
+				if _, ok := in[u]; !ok {
+					t.Errorf("%s.VirtualTree(%v): missing key %d.", name, keys, u)
+				}
+			}
+			if len(vt.Nodes) > 2*len(keys)-1 {
+				t.Errorf("%s.VirtualTree(%v): too many nodes %v.", name, keys, vt.Nodes)
+			}
+			for j, u := range vt.Nodes {
+				// The parent is the deepest proper ancestor in the virtual tree.
+				p := -1
+				for _, v := range vt.Nodes {
+					if w, ok := tree.Lca(u, v); ok && w == v && v != u && (p == -1 || tree.Dist(v, u) < tree.Dist(vt.Nodes[p], u)) {
+						p = in[v]
+					}
+				}
+				if vt.Parent[j] != p {
+					t.Errorf("%s.VirtualTree(%v): parent of %d: expected %d, got %d.", name, keys, u, p, vt.Parent[j])
+				} else if p != -1 && vt.Len[j] != tree.Dist(u, vt.Nodes[p]) {
+					t.Errorf("%s.VirtualTree(%v): length of %d: expected %d, got %d.", name, keys, u, tree.Dist(u, vt.Nodes[p]), vt.Len[j])
+				}
+				for _, v := range vt.Nodes {
+					if w, ok := tree.Lca(u, v); ok {
+						if _, ok := in[w]; !ok {
+							t.Errorf("%s.VirtualTree(%v): missing lca %d.", name, keys, w)
+						}
+					}
+				}
+			}
+		}
+	}
 }
@@ -0,0 +1,55 @@
+package lca
+
+// LcaBatch returns the lca of each pair, or -1 for pairs in different trees.
+// It runs Tarjan's offline algorithm on the dfs order, and needs no tables
+// beyond the forest itself.
+func (f *forest) LcaBatch(ps []Pair) []int {
+	res := make([]int, len(ps))
+	qs := make([][]int, f.n)
+	for i, p := range ps {
+		res[i] = -1
+		qs[p.U] = append(qs[p.U], i)
+		if p.V != p.U {
+			qs[p.V] = append(qs[p.V], i)
+		}
+	}
+
+	// A finished node is unioned into its parent, so find(v) is the deepest
+	// unfinished ancestor of v.
+	set := make([]int, f.n)
+	for u := range set {
+		set[u] = u
+	}
+	find := func(u int) int {
+		r := u
+		for set[r] != r {
+			r = set[r]
+		}
+		for set[u] != r {
+			set[u], u = r, set[u]
+		}
+		return r
+	}
+
+	seen := make([]bool, f.n)
+	path := make([]int, 0, f.n)
+	for _, u := range f.ord {
+		for len(path) > 0 && path[len(path)-1] != f.pnt[u] {
+			x := path[len(path)-1]
+			path = path[:len(path)-1]
+			set[x] = f.pnt[x]
+		}
+		path = append(path, u)
+		seen[u] = true
+		for _, i := range qs[u] {
+			v := ps[i].U
+			if v == u {
+				v = ps[i].V
+			}
+			if seen[v] && f.SameTree(u, v) {
+				res[i] = find(v)
+			}
+		}
+	}
+	return res
+}
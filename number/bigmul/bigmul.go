@@ -0,0 +1,146 @@
+// Package bigmul implements multiplication of large integers by the NTT of
+// the fft package, whose convolutions are exact by CRT, along with the
+// factorials and powers built on it.
+package bigmul
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/kelvinlau/go/fft"
+)
+
+// MaxBase is the max base of the digits, where the convolutions of the
+// longest digit arrays fit in int64.
+const MaxBase = 1 << 16
+
+var (
+	// maxDigits is the max length of the products of the NTT.
+	maxDigits = 1 << 23
+
+	// threshold is the min num of words of the shorter factor, below which
+	// math/big is faster, as measured by BenchmarkMul.
+	threshold = 1 << 17
+)
+
+// MulDigits returns the digits of the product of a and b, where the digits
+// are in little-endian order in base in [2, MaxBase]. The result has no
+// leading zeros, and is empty for 0. The length of the result shall be at
+// most 2^23.
+func MulDigits(a, b []int64, base int64) []int64 {
+	if base < 2 || base > MaxBase {
+		panic(fmt.Sprintf("bigmul: base %d out of [2, %d]", base, MaxBase))
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return []int64{}
+	}
+	c := fft.ConvolveInt64(a, b)
+	var carry int64
+	for i := range c {
+		c[i] += carry
+		c[i], carry = c[i]%base, c[i]/base
+	}
+	for carry > 0 {
+		c = append(c, carry%base)
+		carry /= base
+	}
+	for len(c) > 0 && c[len(c)-1] == 0 {
+		c = c[:len(c)-1]
+	}
+	return c
+}
+
+// digitsPerWord is the num of digits of base 2^16 in a big.Word.
+const digitsPerWord = bits.UintSize / 16
+
+// toDigits returns the digits of |x| in base 2^16.
+func toDigits(x *big.Int) []int64 {
+	ws := x.Bits()
+	d := make([]int64, 0, len(ws)*digitsPerWord)
+	for _, w := range ws {
+		for i := 0; i < digitsPerWord; i++ {
+			d = append(d, int64(w&0xffff))
+			w >>= 16
+		}
+	}
+	return d
+}
+
+// fromDigits returns the integer of the digits in base 2^16.
+func fromDigits(d []int64) *big.Int {
+	ws := make([]big.Word, (len(d)+digitsPerWord-1)/digitsPerWord)
+	for i, x := range d {
+		ws[i/digitsPerWord] |= big.Word(x) << uint(16*(i%digitsPerWord))
+	}
+	return new(big.Int).SetBits(ws)
+}
+
+// Mul returns x * y. The NTT is used if both have at least threshold words,
+// and math/big is used otherwise. The factors too long for the NTT are split.
+func Mul(x, y *big.Int) *big.Int {
+	z := mulAbs(new(big.Int).Abs(x), new(big.Int).Abs(y))
+	if x.Sign()*y.Sign() < 0 {
+		z.Neg(z)
+	}
+	return z
+}
+
+// mulAbs returns x * y for x, y >= 0.
+func mulAbs(x, y *big.Int) *big.Int {
+	nx, ny := len(x.Bits()), len(y.Bits())
+	if nx < ny {
+		x, y, nx, ny = y, x, ny, nx
+	}
+	if ny < threshold {
+		return new(big.Int).Mul(x, y)
+	}
+	if (nx+ny)*digitsPerWord > maxDigits {
+		// x * y == (xh << k + xl) * y, where xl is the lower half words.
+		k := nx / 2
+		ws := x.Bits()
+		xl := new(big.Int).SetBits(append([]big.Word(nil), ws[:k]...))
+		xh := new(big.Int).SetBits(append([]big.Word(nil), ws[k:]...))
+		z := mulAbs(xh, y)
+		z.Lsh(z, uint(k*bits.UintSize))
+		return z.Add(z, mulAbs(xl, y))
+	}
+	return fromDigits(MulDigits(toDigits(x), toDigits(y), MaxBase))
+}
+
+// Factorial returns n!, by multiplying the halves of the product recursively,
+// so the factors are of similar sizes.
+func Factorial(n int64) *big.Int {
+	if n < 2 {
+		return big.NewInt(1)
+	}
+	return product(2, n)
+}
+
+// product returns the product of [l, r].
+func product(l, r int64) *big.Int {
+	if r-l < 16 {
+		z := big.NewInt(l)
+		for i := l + 1; i <= r; i++ {
+			z.Mul(z, big.NewInt(i))
+		}
+		return z
+	}
+	m := (l + r) / 2
+	return Mul(product(l, m), product(m+1, r))
+}
+
+// Pow returns x^k, by repeated squaring.
+func Pow(x *big.Int, k uint64) *big.Int {
+	z := big.NewInt(1)
+	p := new(big.Int).Set(x)
+	for ; k > 0; k >>= 1 {
+		if k&1 != 0 {
+			z = Mul(z, p)
+		}
+		if k > 1 {
+			p = Mul(p, p)
+		}
+	}
+	return z
+}
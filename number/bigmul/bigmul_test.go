@@ -0,0 +1,109 @@
+package bigmul
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func randomInt(words int) *big.Int {
+	x := new(big.Int).Rand(rand.New(rand.NewSource(rand.Int63())), new(big.Int).Lsh(big.NewInt(1), uint(64*words)))
+	if rand.Intn(2) == 0 {
+		x.Neg(x)
+	}
+	return x
+}
+
+func TestMulDigits(t *testing.T) {
+	for it := 0; it < 100; it++ {
+		base := []int64{2, 10, 10000, MaxBase}[it%4]
+		a := make([]int64, rand.Intn(100))
+		b := make([]int64, rand.Intn(100))
+		x, y := new(big.Int), new(big.Int)
+		for _, d := range []struct {
+			ds []int64
+			x  *big.Int
+		}{{a, x}, {b, y}} {
+			for i := range d.ds {
+				d.ds[i] = rand.Int63n(base)
+			}
+			for i := len(d.ds) - 1; i >= 0; i-- {
+				d.x.Mul(d.x, big.NewInt(base))
+				d.x.Add(d.x, big.NewInt(d.ds[i]))
+			}
+		}
+		c := MulDigits(a, b, base)
+		z := new(big.Int)
+		for i := len(c) - 1; i >= 0; i-- {
+			if c[i] < 0 || c[i] >= base {
+				t.Fatalf("MulDigits: digit %d out of base %d.", c[i], base)
+			}
+			z.Mul(z, big.NewInt(base))
+			z.Add(z, big.NewInt(c[i]))
+		}
+		if e := new(big.Int).Mul(x, y); z.Cmp(e) != 0 || len(c) > 0 && c[len(c)-1] == 0 {
+			t.Fatalf("MulDigits(%v, %v, %d): expected %v, got %v.", a, b, base, e, c)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	// Shrink the limits to test the NTT and the splits on small inputs.
+	defer func(th, md int) { threshold, maxDigits = th, md }(threshold, maxDigits)
+	threshold, maxDigits = 16, 1<<10
+	for _, n := range []int{0, 1, 10, threshold, 3 * threshold, 200} {
+		for it := 0; it < 3; it++ {
+			x, y := randomInt(n), randomInt(n+rand.Intn(100))
+			if z, e := Mul(x, y), new(big.Int).Mul(x, y); z.Cmp(e) != 0 {
+				t.Fatalf("Mul of %d words: expected %v, got %v.", n, e, z)
+			}
+		}
+	}
+	x := randomInt(50)
+	x.Abs(x)
+	if z, e := Mul(x, new(big.Int).Neg(x)), new(big.Int).Mul(x, new(big.Int).Neg(x)); z.Cmp(e) != 0 {
+		t.Fatalf("Mul of negative: expected %v, got %v.", e, z)
+	}
+}
+
+func TestFactorial(t *testing.T) {
+	defer func(th int) { threshold = th }(threshold)
+	threshold = 64
+	for _, n := range []int64{0, 1, 5, 20, 100, 30000} {
+		if z, e := Factorial(n), new(big.Int).MulRange(1, n); z.Cmp(e) != 0 {
+			t.Errorf("Factorial(%d): expected %v, got %v.", n, e, z)
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	defer func(th int) { threshold = th }(threshold)
+	threshold = 64
+	for _, c := range []struct {
+		x int64
+		k uint64
+	}{{2, 0}, {0, 5}, {-3, 7}, {12345, 10000}, {-987654321, 20001}} {
+		x := big.NewInt(c.x)
+		if z, e := Pow(x, c.k), new(big.Int).Exp(x, new(big.Int).SetUint64(c.k), nil); z.Cmp(e) != 0 {
+			t.Errorf("Pow(%d, %d): expected %v, got %v.", c.x, c.k, e, z)
+		}
+	}
+}
+
+// BenchmarkMul tunes threshold, by comparing the NTT and math/big.
+func BenchmarkMul(b *testing.B) {
+	for _, n := range []int{1 << 12, 1 << 14, 1 << 16, 1 << 17, 1 << 18} {
+		x, y := randomInt(n), randomInt(n)
+		b.Run(fmt.Sprintf("ntt/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fromDigits(MulDigits(toDigits(x), toDigits(y), MaxBase))
+			}
+		})
+		b.Run(fmt.Sprintf("big/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				new(big.Int).Mul(x, y)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package number
 
 import (
 	"math"
+	"math/bits"
 	"sort"
 )
 
@@ -12,18 +13,8 @@ func ModularMultiply(x, y, r int64) int64 {
 		return x * y % r
 	}
 
-	if x < y {
-		x, y = y, x
-	}
-	z := int64(0)
-	for y > 0 {
-		if y&1 != 0 {
-			z = (z + x) % r
-		}
-		x = x * 2 % r
-		y >>= 1
-	}
-	return z
+	hi, lo := bits.Mul64(uint64(mod(x, r)), uint64(mod(y, r)))
+	return int64(bits.Rem64(hi, lo, uint64(r)))
 }
 
 // Returns x ^ y % r.
@@ -122,3 +113,46 @@ func ModularLog(a, b, m int64) int64 {
 	}
 	return -1
 }
+
+// ModularSqrt returns the least x such that x * x % p == a % p for a prime p,
+// or -1 if none, by Tonelli-Shanks.
+func ModularSqrt(a, p int64) int64 {
+	a = mod(a, p)
+	if a == 0 || p == 2 {
+		return a
+	}
+	if ModularPower(a, (p-1)/2, p) != 1 {
+		return -1
+	}
+	// p - 1 == q * 2^s, where q is odd, and z is a non-residue.
+	q, s := p-1, 0
+	for q%2 == 0 {
+		q, s = q/2, s+1
+	}
+	z := int64(2)
+	for ModularPower(z, (p-1)/2, p) == 1 {
+		z++
+	}
+	c := ModularPower(z, q, p)
+	x := ModularPower(a, (q+1)/2, p)
+	u := ModularPower(a, q, p)
+	// Keep x * x == a * u, where the order of u decreases each round.
+	for u != 1 {
+		i, v := 0, u
+		for v != 1 {
+			v, i = ModularMultiply(v, v, p), i+1
+		}
+		b := c
+		for j := 0; j < s-i-1; j++ {
+			b = ModularMultiply(b, b, p)
+		}
+		x = ModularMultiply(x, b, p)
+		c = ModularMultiply(b, b, p)
+		u = ModularMultiply(u, c, p)
+		s = i
+	}
+	if p-x < x {
+		x = p - x
+	}
+	return x
+}
@@ -1,6 +1,22 @@
 package number
 
-import "testing"
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestModularMultiply(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		r := rand.Int63n(1<<62) + 1
+		x, y := rand.Int63n(r), rand.Int63n(r)
+		e := new(big.Int).Mul(big.NewInt(x), big.NewInt(y))
+		e.Mod(e, big.NewInt(r))
+		if g := ModularMultiply(x, y, r); g != e.Int64() {
+			t.Errorf("%d * %d %% %d should be %d, got %d.", x, y, r, e.Int64(), g)
+		}
+	}
+}
 
 func TestModularPower(t *testing.T) {
 	test := func(a, b, m, e int64) {
@@ -70,3 +86,21 @@ func TestModularLog(t *testing.T) {
 	test(23, 23, 100003)
 	test(56, 7, 10007)
 }
+
+func TestModularSqrt(t *testing.T) {
+	for _, p := range []int64{2, 3, 5, 7, 13, 17, 97, 998244353, 1000000007} {
+		for i := 0; i < 200; i++ {
+			a := rand.Int63n(p)
+			x := ModularSqrt(a, p)
+			if x == -1 {
+				if ModularPower(a, (p-1)/2, p) == 1 {
+					t.Errorf("ModularSqrt(%d, %d): expected a root, got -1.", a, p)
+				}
+				continue
+			}
+			if x*2 > p || ModularMultiply(x, x, p) != a {
+				t.Errorf("ModularSqrt(%d, %d): got %d.", a, p, x)
+			}
+		}
+	}
+}
@@ -1,6 +1,6 @@
 package number
 
-// IsPrimitiveRoot reports if w is a primitive root of r.
+// IsPrimitiveRoot reports if w is a primitive root of a prime r.
 func IsPrimitiveRoot(r, w int64) bool {
 	n := r - 1
 	if w <= 1 || ModularPower(w, n, r) != 1 {
@@ -16,12 +16,56 @@ func IsPrimitiveRoot(r, w int64) bool {
 	return true
 }
 
-// PrimitiveRoot returns a primitive root of n.
+// PrimitiveRoot returns the least primitive root of n, or 0 if none.
 func PrimitiveRoot(n int64) int64 {
+	if n < 1 {
+		return 0
+	}
+	if n <= 2 {
+		return n - 1
+	}
+	phi := totient(n)
+	qs := primeFactors(phi)
 	for x := int64(2); x < n; x++ {
-		if IsPrimitiveRoot(x, n) {
+		if GCD(x, n) != 1 {
+			continue
+		}
+		ok := true
+		for _, q := range qs {
+			if ModularPower(x, phi/q, n) == 1 {
+				ok = false
+				break
+			}
+		}
+		if ok {
 			return x
 		}
 	}
 	return 0
 }
+
+// primeFactors returns the distinct prime factors of n, by trial division.
+func primeFactors(n int64) []int64 {
+	var qs []int64
+	for d := int64(2); d*d <= n; d++ {
+		if n%d == 0 {
+			qs = append(qs, d)
+			for n%d == 0 {
+				n /= d
+			}
+		}
+	}
+	if n > 1 {
+		qs = append(qs, n)
+	}
+	return qs
+}
+
+// totient returns Euler's totient of n.
+func totient(n int64) int64 {
+	phi := n
+	for _, q := range primeFactors(n) {
+		phi = phi / q * (q - 1)
+	}
+	return phi
+}
@@ -9,11 +9,24 @@ func TestPrimitiveRoot(t *testing.T) {
 	for n := int64(2); n < 100; n++ {
 		t.Logf("Primitive root of %d is %d.", n, PrimitiveRoot(n))
 	}
-	if PrimitiveRoot(6) != 0 {
-		t.Errorf("Primitive root of %d is %d, got %d.", 6, 0, PrimitiveRoot(6))
+	for _, c := range []struct{ n, r int64 }{
+		{-5, 0}, {0, 0}, {2, 1}, {4, 3}, {6, 5}, {7, 3}, {8, 0}, {90, 0}, {98, 3},
+		{998244353, 3}, {469762049, 3}, {167772161, 3}, {1004535809, 3},
+	} {
+		if r := PrimitiveRoot(c.n); r != c.r {
+			t.Errorf("Primitive root of %d is %d, got %d.", c.n, c.r, r)
+		}
 	}
-	if PrimitiveRoot(90) != 11 {
-		t.Errorf("Primitive root of %d is %d, got %d.", 90, 11, PrimitiveRoot(90))
+	for n := int64(3); n < 1000; n++ {
+		if !IsPrime(n) {
+			continue
+		}
+		r := PrimitiveRoot(n)
+		for x := int64(1); x <= r; x++ {
+			if IsPrimitiveRoot(n, x) != (x == r) {
+				t.Errorf("IsPrimitiveRoot(%d, %d): expected %v.", n, x, x == r)
+			}
+		}
 	}
 }
 
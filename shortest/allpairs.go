@@ -0,0 +1,95 @@
+package shortest
+
+import "github.com/kelvinlau/go/graph"
+
+// AllPairs is the result of an all pairs shortest paths algorithm.
+type AllPairs struct {
+	// Dist[u][v] is the distance from u to v.
+	Dist [][]int64
+	// pre[u][v] is the predecessor of v on the shortest path from u.
+	pre [][]int
+}
+
+// Path returns the shortest path from u to v, or nil if v is unreachable.
+func (a *AllPairs) Path(u, v int) []int {
+	if a.Dist[u][v] == Inf {
+		return nil
+	}
+	return Path(a.pre[u], v)
+}
+
+// FloydWarshall returns the all pairs shortest paths in O(n^3), allowing
+// negative weights, or ok == false if there is a negative cycle.
+func FloydWarshall(g *graph.Graph) (a *AllPairs, ok bool) {
+	n := g.N()
+	d := make([][]int64, n)
+	pre := make([][]int, n)
+	for u := range d {
+		d[u] = make([]int64, n)
+		pre[u] = make([]int, n)
+		for v := range d[u] {
+			d[u][v] = Inf
+			pre[u][v] = -1
+		}
+		d[u][u] = 0
+	}
+	for _, e := range g.Edges() {
+		for _, p := range [][2]int{{e.U, e.V}, {e.V, e.U}} {
+			u, v := p[0], p[1]
+			if e.W < d[u][v] {
+				d[u][v] = e.W
+				pre[u][v] = u
+			}
+			if g.Directed() {
+				break
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if d[i][k] == Inf {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if d[k][j] != Inf && d[i][k]+d[k][j] < d[i][j] {
+					d[i][j] = d[i][k] + d[k][j]
+					pre[i][j] = pre[k][j]
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			if d[i][i] < 0 {
+				return nil, false
+			}
+		}
+	}
+	for u := range pre {
+		pre[u][u] = -1
+	}
+	return &AllPairs{d, pre}, true
+}
+
+// Johnson returns the all pairs shortest paths in O(nm log(n)), allowing
+// negative weights, or ok == false if there is a negative cycle. The weights
+// are made non-negative by the potentials from BellmanFord, then Dijkstra is
+// run from each vertex.
+func Johnson(g *graph.Graph) (a *AllPairs, ok bool) {
+	n := g.N()
+	es := g.Edges()
+	h := make([]int64, n)
+	if _, cycle := bellmanFord(g, h); cycle != nil {
+		return nil, false
+	}
+	w := func(u, v, id int) int64 { return es[id].W + h[u] - h[v] }
+	a = &AllPairs{make([][]int64, n), make([][]int, n)}
+	for u := 0; u < n; u++ {
+		d, pre := dijkstra(g, u, w)
+		for v := range d {
+			if d[v] != Inf {
+				d[v] += h[v] - h[u]
+			}
+		}
+		a.Dist[u], a.pre[u] = d, pre
+	}
+	return a, true
+}
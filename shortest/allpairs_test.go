@@ -0,0 +1,51 @@
+package shortest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/graph"
+)
+
+func TestAllPairs(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(8) + 1
+		var g *graph.Graph
+		if it%4 == 0 {
+			g = randomGraph(n, rand.Intn(15), 0, 20, false)
+		} else {
+			g = randomGraph(n, rand.Intn(15), -3, 20, true)
+		}
+		negative := false
+		for s := 0; s < n; s++ {
+			if _, _, c := BellmanFord(g, s); c != nil {
+				negative = true
+			}
+		}
+		for name, f := range map[string]func(*graph.Graph) (*AllPairs, bool){
+			"FloydWarshall": FloydWarshall,
+			"Johnson":       Johnson,
+		} {
+			a, ok := f(g)
+			if ok == negative {
+				t.Fatalf("%s(%v): expected %v, got %v.", name, g.Edges(), !negative, ok)
+			}
+			if !ok {
+				continue
+			}
+			for u := 0; u < n; u++ {
+				d := brute(g, u)
+				for v := 0; v < n; v++ {
+					if a.Dist[u][v] != d[v] {
+						t.Fatalf("%s(%v): Dist[%d]: expected %v, got %v.", name, g.Edges(), u, d, a.Dist[u])
+					}
+					if p := a.Path(u, v); d[v] == Inf && p != nil {
+						t.Fatalf("%s: Path(%d, %d): expected nil, got %v.", name, u, v, p)
+					} else if d[v] != Inf {
+						checkPath(t, name, g, p, u, v, d[v])
+					}
+				}
+			}
+		}
+	}
+}
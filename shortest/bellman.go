@@ -0,0 +1,60 @@
+package shortest
+
+import "github.com/kelvinlau/go/graph"
+
+// BellmanFord returns the distances from s and the predecessors on the
+// shortest paths, allowing negative weights. If a negative cycle is reachable
+// from s, it is returned as a list of vertices, and the distances are not
+// meaningful.
+func BellmanFord(g *graph.Graph, s int) (dist []int64, pre []int, cycle []int) {
+	dist = make([]int64, g.N())
+	for u := range dist {
+		dist[u] = Inf
+	}
+	dist[s] = 0
+	pre, cycle = bellmanFord(g, dist)
+	return dist, pre, cycle
+}
+
+// bellmanFord relaxes the arcs until no distance changes, at most n rounds,
+// given the initial distances.
+func bellmanFord(g *graph.Graph, dist []int64) (pre []int, cycle []int) {
+	n := g.N()
+	es := g.Edges()
+	pre = make([]int, n)
+	for u := range pre {
+		pre[u] = -1
+	}
+	for i := 0; i < n; i++ {
+		x := -1
+		for u := 0; u < n; u++ {
+			if dist[u] == Inf {
+				continue
+			}
+			ids := g.EdgeIDs(u)
+			for k, v := range g.Adj(u) {
+				if d := dist[u] + es[ids[k]].W; d < dist[v] {
+					dist[v] = d
+					pre[v] = u
+					x = v
+				}
+			}
+		}
+		if x == -1 {
+			return pre, nil
+		}
+		if i == n-1 {
+			// x is changed in the n-th round, so it's reached from a negative
+			// cycle by the predecessors.
+			for j := 0; j < n; j++ {
+				x = pre[x]
+			}
+			cycle = append(cycle, x)
+			for v := pre[x]; v != x; v = pre[v] {
+				cycle = append(cycle, v)
+			}
+			reverse(cycle)
+		}
+	}
+	return pre, cycle
+}
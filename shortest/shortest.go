@@ -0,0 +1,181 @@
+// Package shortest implements the shortest paths algorithms on graph.Graph,
+// whose edge weights are int64. Graphs of adjacency lists, e.g. the ones for
+// lca.Tree or blossom, are converted by graph.FromAdj with unit weights, or by
+// graph.FromWeightedAdj, e.g. with lca.Tree.Weights(). The distances to the
+// unreachable vertices are Inf, and paths are lists of vertices.
+package shortest
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/kelvinlau/go/graph"
+)
+
+// Inf is the distance to unreachable vertices.
+const Inf = math.MaxInt64
+
+// Dijkstra returns the distances from s and the predecessors on the shortest
+// paths, where pre[v] == -1 for s and the unreachable vertices. The weights
+// shall be non-negative.
+func Dijkstra(g *graph.Graph, s int) (dist []int64, pre []int) {
+	es := g.Edges()
+	return dijkstra(g, s, func(u, v, id int) int64 { return es[id].W })
+}
+
+// dijkstra is Dijkstra where w(u, v, id) is the weight of the arc (u, v) of
+// the edge id.
+func dijkstra(g *graph.Graph, s int, w func(u, v, id int) int64) ([]int64, []int) {
+	n := g.N()
+	dist := make([]int64, n)
+	pre := make([]int, n)
+	for u := range dist {
+		dist[u] = Inf
+		pre[u] = -1
+	}
+	dist[s] = 0
+	h := &items{{s, 0}}
+	for h.Len() > 0 {
+		it := heap.Pop(h).(item)
+		u := it.u
+		if it.d > dist[u] {
+			continue
+		}
+		ids := g.EdgeIDs(u)
+		for k, v := range g.Adj(u) {
+			if d := it.d + w(u, v, ids[k]); d < dist[v] {
+				dist[v] = d
+				pre[v] = u
+				heap.Push(h, item{v, d})
+			}
+		}
+	}
+	return dist, pre
+}
+
+type item struct {
+	u int
+	d int64
+}
+
+type items []item
+
+func (h items) Len() int            { return len(h) }
+func (h items) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h items) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *items) Push(x interface{}) { *h = append(*h, x.(item)) }
+func (h *items) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// DijkstraFloat is Dijkstra with the float64 weights w indexed by edge, where
+// the distances to the unreachable vertices are +Inf.
+func DijkstraFloat(g *graph.Graph, w []float64, s int) (dist []float64, pre []int) {
+	n := g.N()
+	dist = make([]float64, n)
+	pre = make([]int, n)
+	for u := range dist {
+		dist[u] = math.Inf(1)
+		pre[u] = -1
+	}
+	dist[s] = 0
+	h := &floatItems{{s, 0}}
+	for h.Len() > 0 {
+		it := heap.Pop(h).(floatItem)
+		u := it.u
+		if it.d > dist[u] {
+			continue
+		}
+		ids := g.EdgeIDs(u)
+		for k, v := range g.Adj(u) {
+			if d := it.d + w[ids[k]]; d < dist[v] {
+				dist[v] = d
+				pre[v] = u
+				heap.Push(h, floatItem{v, d})
+			}
+		}
+	}
+	return dist, pre
+}
+
+type floatItem struct {
+	u int
+	d float64
+}
+
+type floatItems []floatItem
+
+func (h floatItems) Len() int            { return len(h) }
+func (h floatItems) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h floatItems) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *floatItems) Push(x interface{}) { *h = append(*h, x.(floatItem)) }
+func (h *floatItems) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// ZeroOneBFS is Dijkstra in linear time for the weights of 0 or 1.
+func ZeroOneBFS(g *graph.Graph, s int) (dist []int64, pre []int) {
+	n := g.N()
+	es := g.Edges()
+	dist = make([]int64, n)
+	pre = make([]int, n)
+	for u := range dist {
+		dist[u] = Inf
+		pre[u] = -1
+	}
+	dist[s] = 0
+	done := make([]bool, n)
+	// The deque is the stack front followed by the queue back[head:].
+	front, back, head := []int{s}, []int(nil), 0
+	for len(front) > 0 || head < len(back) {
+		var u int
+		if len(front) > 0 {
+			u = front[len(front)-1]
+			front = front[:len(front)-1]
+		} else {
+			u = back[head]
+			head++
+		}
+		if done[u] {
+			continue
+		}
+		done[u] = true
+		ids := g.EdgeIDs(u)
+		for k, v := range g.Adj(u) {
+			w := es[ids[k]].W
+			if d := dist[u] + w; d < dist[v] {
+				dist[v] = d
+				pre[v] = u
+				if w == 0 {
+					front = append(front, v)
+				} else {
+					back = append(back, v)
+				}
+			}
+		}
+	}
+	return dist, pre
+}
+
+// Path returns the path to t given the predecessors from a single source
+// algorithm, where t shall be reachable.
+func Path(pre []int, t int) []int {
+	var p []int
+	for v := t; v != -1; v = pre[v] {
+		p = append(p, v)
+	}
+	reverse(p)
+	return p
+}
+
+func reverse(a []int) {
+	for i, j := 0, len(a)-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+}
@@ -0,0 +1,202 @@
+package shortest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kelvinlau/go/graph"
+	"github.com/kelvinlau/go/lca"
+)
+
+func randomGraph(n, m int, lo, hi int64, directed bool) *graph.Graph {
+	es := make([]graph.Edge, m)
+	for i := range es {
+		es[i] = graph.Edge{U: rand.Intn(n), V: rand.Intn(n), W: lo + rand.Int63n(hi-lo+1)}
+	}
+	if directed {
+		return graph.New(n, es)
+	}
+	return graph.NewUndirected(n, es)
+}
+
+// weight returns the min weight of the arcs (u, v), or Inf if none.
+func weight(g *graph.Graph, u, v int) int64 {
+	w := int64(Inf)
+	ids := g.EdgeIDs(u)
+	for k, x := range g.Adj(u) {
+		if x == v && g.Edges()[ids[k]].W < w {
+			w = g.Edges()[ids[k]].W
+		}
+	}
+	return w
+}
+
+// brute returns the distances from s by relaxing n times, assuming no
+// negative cycles.
+func brute(g *graph.Graph, s int) []int64 {
+	n := g.N()
+	d := make([]int64, n)
+	for u := range d {
+		d[u] = Inf
+	}
+	d[s] = 0
+	for i := 0; i < n; i++ {
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if w := weight(g, u, v); d[u] != Inf && w != Inf && d[u]+w < d[v] {
+					d[v] = d[u] + w
+				}
+			}
+		}
+	}
+	return d
+}
+
+func checkPath(t *testing.T, name string, g *graph.Graph, p []int, s, v int, d int64) {
+	if p[0] != s || p[len(p)-1] != v {
+		t.Fatalf("%s: path %v is not from %d to %d.", name, p, s, v)
+	}
+	var sum int64
+	for i := 0; i+1 < len(p); i++ {
+		w := weight(g, p[i], p[i+1])
+		if w == Inf {
+			t.Fatalf("%s: path %v has no arc (%d, %d).", name, p, p[i], p[i+1])
+		}
+		sum += w
+	}
+	if sum != d {
+		t.Fatalf("%s: path %v of length %d, expected %d.", name, p, sum, d)
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	for it := 0; it < 300; it++ {
+		n := rand.Intn(10) + 1
+		hi := int64(20)
+		if it%2 == 0 {
+			hi = 1
+		}
+		g := randomGraph(n, rand.Intn(30), 0, hi, it%3 != 0)
+		s := rand.Intn(n)
+		expected := brute(g, s)
+		w := make([]float64, len(g.Edges()))
+		for i, e := range g.Edges() {
+			w[i] = float64(e.W)
+		}
+		fd, fpre := DijkstraFloat(g, w, s)
+		algs := map[string]func(*graph.Graph, int) ([]int64, []int){
+			"Dijkstra": Dijkstra,
+			"BellmanFord": func(g *graph.Graph, s int) ([]int64, []int) {
+				d, pre, _ := BellmanFord(g, s)
+				return d, pre
+			},
+			"DijkstraFloat": func(g *graph.Graph, s int) ([]int64, []int) {
+				d := make([]int64, n)
+				for v := range d {
+					d[v] = Inf
+					if fd[v] < Inf {
+						d[v] = int64(fd[v])
+					}
+				}
+				return d, fpre
+			},
+		}
+		if hi == 1 {
+			algs["ZeroOneBFS"] = ZeroOneBFS
+		}
+		for name, f := range algs {
+			dist, pre := f(g, s)
+			for v := 0; v < n; v++ {
+				if dist[v] != expected[v] {
+					t.Fatalf("%s(%d): expected %v, got %v.", name, s, expected, dist)
+				}
+				if dist[v] != Inf {
+					checkPath(t, name, g, Path(pre, v), s, v, dist[v])
+				}
+			}
+		}
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	for it := 0; it < 500; it++ {
+		n := rand.Intn(8) + 1
+		g := randomGraph(n, rand.Intn(15), -5, 20, true)
+		s := rand.Intn(n)
+		// A negative cycle is reachable iff some distance keeps decreasing.
+		d := brute(g, s)
+		negative := false
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if w := weight(g, u, v); d[u] != Inf && w != Inf && d[u]+w < d[v] {
+					negative = true
+				}
+			}
+		}
+		dist, pre, cycle := BellmanFord(g, s)
+		if negative != (cycle != nil) {
+			t.Fatalf("BellmanFord(%v): expected negative cycle %v, got %v.", g.Edges(), negative, cycle)
+		}
+		if negative {
+			var sum int64
+			for i, u := range cycle {
+				w := weight(g, u, cycle[(i+1)%len(cycle)])
+				if w == Inf {
+					t.Fatalf("BellmanFord(%v): cycle %v has no arc from %d.", g.Edges(), cycle, u)
+				}
+				sum += w
+			}
+			if sum >= 0 {
+				t.Fatalf("BellmanFord(%v): cycle %v of length %d.", g.Edges(), cycle, sum)
+			}
+			continue
+		}
+		for v := 0; v < n; v++ {
+			if dist[v] != d[v] {
+				t.Fatalf("BellmanFord(%v): expected %v, got %v.", g.Edges(), d, dist)
+			}
+			if dist[v] != Inf {
+				checkPath(t, "BellmanFord", g, Path(pre, v), s, v, dist[v])
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	g := randomGraph(100000, 500000, 0, 1000000, true)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Dijkstra(g, 0)
+	}
+}
+
+func TestFromAdj(t *testing.T) {
+	// The path 0 - 1 - 2 - 3 of weights 5, 1, 2.
+	tr := lca.NewTree(4)
+	tr.LinkWeighted(0, 1, 5)
+	tr.LinkWeighted(1, 2, 1)
+	tr.LinkWeighted(2, 3, 2)
+	tr.Build()
+	for _, c := range []struct {
+		name string
+		g    *graph.Graph
+		e    []int64
+	}{
+		{"FromAdj", graph.FromAdj(tr.Adj()), []int64{3, 2, 1, 0}},
+		{"FromWeightedAdj", graph.FromWeightedAdj(tr.Adj(), tr.Weights()), []int64{8, 3, 2, 0}},
+	} {
+		dist, _ := Dijkstra(c.g, 3)
+		testSliceEquals(t, c.name, dist, c.e)
+		dist, _, _ = BellmanFord(c.g, 3)
+		testSliceEquals(t, c.name, dist, c.e)
+	}
+}
+
+func testSliceEquals(t *testing.T, name string, a, e []int64) {
+	for i := range e {
+		if len(a) != len(e) || a[i] != e[i] {
+			t.Errorf("%s: expected %v, got %v.", name, e, a)
+			return
+		}
+	}
+}
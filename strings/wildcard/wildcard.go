@@ -0,0 +1,109 @@
+// Package wildcard implements string matching with wildcards by convolutions.
+package wildcard
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/kelvinlau/go/fft"
+	"github.com/kelvinlau/go/number"
+)
+
+// Wildcard matches any rune in the pattern.
+const Wildcard = '?'
+
+// mod is the prime 2^61-1, which the codes are modulo.
+const mod = 1<<61 - 1
+
+// maxLen is the max length of the convolutions, as fft.ConvolveMod supports.
+var maxLen = 1 << 23
+
+// Match returns the positions, in runes, of the text where the pattern
+// matches, in O((n+m) log(n+m)). The position i matches iff the sum of
+// p[j] * t[i+j] * (p[j] - t[i+j])^2 over j is zero, where the runes are coded
+// by random values, and the wildcards by 0. A false match happens with
+// probability about 4 / 2^61 per position. Long texts are matched in blocks
+// overlapping by m-1 runes, so the text may be of any length, while it panics
+// if the pattern is longer than 2^22 runes.
+func Match(text, pattern string) []int {
+	t, p := []rune(text), []rune(pattern)
+	n, m := len(t), len(p)
+	if m > n {
+		return nil
+	}
+	if m == 0 {
+		ps := make([]int, n+1)
+		for i := range ps {
+			ps[i] = i
+		}
+		return ps
+	}
+	codes := map[rune]int64{}
+	code := func(r rune) int64 {
+		c, ok := codes[r]
+		if !ok {
+			c = rand.Int63n(mod-1) + 1
+			codes[r] = c
+		}
+		return c
+	}
+	// The powers of the codes, where the pattern is reversed, so the sums are
+	// the terms of index i+m-1 of the convolutions.
+	var tp, pp [4][]int64
+	for k := 1; k <= 3; k++ {
+		tp[k], pp[k] = make([]int64, n), make([]int64, m)
+	}
+	for i, r := range t {
+		c := code(r)
+		tp[1][i] = c
+		tp[2][i] = number.ModularMultiply(c, c, mod)
+		tp[3][i] = number.ModularMultiply(tp[2][i], c, mod)
+	}
+	for j, r := range p {
+		if r == Wildcard {
+			continue
+		}
+		c := code(r)
+		k := m - 1 - j
+		pp[1][k] = c
+		pp[2][k] = number.ModularMultiply(c, c, mod)
+		pp[3][k] = number.ModularMultiply(pp[2][k], c, mod)
+	}
+	// Each block of the text is b+m-1 runes for b positions, so the
+	// convolutions are at most maxLen long.
+	b := maxLen - 2*(m-1)
+	if b < 1 {
+		panic(fmt.Sprintf("wildcard: pattern of length %d exceeds %d", m, maxLen/2))
+	}
+	var ps []int
+	for s := 0; s+m <= n; s += b {
+		e := s + b + m - 1
+		if e > n {
+			e = n
+		}
+		var bp [4][]int64
+		for k := 1; k <= 3; k++ {
+			bp[k] = tp[k][s:e]
+		}
+		ps = matches(ps, bp, pp, s)
+	}
+	return ps
+}
+
+// matches appends to ps the positions s+i where the pattern matches at t[i],
+// given the powers of the codes of the text block t, and of the reversed
+// pattern.
+func matches(ps []int, tp, pp [4][]int64, s int) []int {
+	n, m := len(tp[1]), len(pp[1])
+	// p^3 t - 2 p^2 t^2 + p t^3.
+	a := fft.ConvolveMod(pp[3], tp[1], mod)
+	b := fft.ConvolveMod(pp[2], tp[2], mod)
+	c := fft.ConvolveMod(pp[1], tp[3], mod)
+	for i := 0; i+m <= n; i++ {
+		k := i + m - 1
+		if (a[k]+c[k])%mod == 2*b[k]%mod {
+			ps = append(ps, s+i)
+		}
+	}
+	return ps
+}
@@ -0,0 +1,77 @@
+package wildcard
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func bruteMatch(text, pattern string) []int {
+	t, p := []rune(text), []rune(pattern)
+	var ps []int
+	for i := 0; i+len(p) <= len(t); i++ {
+		ok := true
+		for j, r := range p {
+			if r != Wildcard && r != t[i+j] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ps = append(ps, i)
+		}
+	}
+	return ps
+}
+
+func randomString(n int, alphabet []rune) string {
+	s := make([]rune, n)
+	for i := range s {
+		s[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(s)
+}
+
+func TestMatch(t *testing.T) {
+	test := func(text, pattern string) {
+		e, g := bruteMatch(text, pattern), Match(text, pattern)
+		if len(e) != len(g) {
+			t.Fatalf("Match(%q, %q): expected %v, got %v.", text, pattern, e, g)
+		}
+		for i := range e {
+			if e[i] != g[i] {
+				t.Fatalf("Match(%q, %q): expected %v, got %v.", text, pattern, e, g)
+			}
+		}
+	}
+	test("abracadabra", "a?ra")
+	test("abracadabra", "")
+	test("ab", "abc")
+	test("héllo wörld", "?ö?")
+	test("a?c", "a?c")
+	for it := 0; it < 200; it++ {
+		text := randomString(rand.Intn(100), []rune("ab"))
+		pattern := randomString(rand.Intn(8), []rune("ab??"))
+		test(text, pattern)
+	}
+	text := randomString(100000, []rune("abcdefgh"))
+	test(text, text[500:520]+"?"+text[521:600])
+
+	// Blocks of 64 - 2 * (m - 1) positions.
+	defer func(ml int) { maxLen = ml }(maxLen)
+	maxLen = 64
+	for it := 0; it < 200; it++ {
+		text := randomString(rand.Intn(300), []rune("ab"))
+		pattern := randomString(1+rand.Intn(20), []rune("ab??"))
+		test(text, pattern)
+	}
+	test(strings.Repeat("a", 1000), "a?")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Match with a pattern of 33 runes: expected a panic.")
+			}
+		}()
+		Match(strings.Repeat("a", 100), strings.Repeat("a", 33))
+	}()
+}
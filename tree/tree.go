@@ -0,0 +1,215 @@
+// Package tree implements common algorithms on unrooted trees: diameter,
+// centers, rerooting dp, and canonical hashing for isomorphism checks.
+//
+// A tree is given by its adjacency lists, the same as lca.Tree.Adj(). All the
+// traversals are iterative, so path-like trees with millions of nodes are
+// fine.
+package tree
+
+import (
+	"sort"
+	"strconv"
+)
+
+// bfs returns the nodes in the tree of r in bfs order, along with the parents
+// and the distances from r. The node cut, if not -1, is treated as removed.
+func bfs(adj [][]int, r, cut int) (ord, pnt, dist []int) {
+	n := len(adj)
+	pnt = make([]int, n)
+	dist = make([]int, n)
+	for u := range dist {
+		dist[u] = -1
+	}
+	if cut != -1 {
+		dist[cut] = -2
+	}
+	pnt[r] = -1
+	dist[r] = 0
+	ord = append(ord, r)
+	for i := 0; i < len(ord); i++ {
+		u := ord[i]
+		for _, v := range adj[u] {
+			if dist[v] == -1 {
+				pnt[v] = u
+				dist[v] = dist[u] + 1
+				ord = append(ord, v)
+			}
+		}
+	}
+	return
+}
+
+// forest returns all the nodes in bfs order tree by tree, along with the
+// parents, which are -1 for the roots.
+func forest(adj [][]int) (ord, pnt []int) {
+	n := len(adj)
+	pnt = make([]int, n)
+	seen := make([]bool, n)
+	ord = make([]int, 0, n)
+	for r := range adj {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		pnt[r] = -1
+		ord = append(ord, r)
+		for i := len(ord) - 1; i < len(ord); i++ {
+			u := ord[i]
+			for _, v := range adj[u] {
+				if !seen[v] {
+					seen[v] = true
+					pnt[v] = u
+					ord = append(ord, v)
+				}
+			}
+		}
+	}
+	return
+}
+
+// Diameter returns the length of the longest path in the tree containing node
+// 0, and the path itself.
+func Diameter(adj [][]int) (d int, path []int) {
+	if len(adj) == 0 {
+		return 0, nil
+	}
+	ord, _, _ := bfs(adj, 0, -1)
+	u := ord[len(ord)-1]
+	ord, pnt, dist := bfs(adj, u, -1)
+	v := ord[len(ord)-1]
+	for x := v; x != -1; x = pnt[x] {
+		path = append(path, x)
+	}
+	return dist[v], path
+}
+
+// Centers returns the one or two centers of the tree containing node 0, i.e.
+// the nodes minimizing the max distance to the other nodes.
+func Centers(adj [][]int) []int {
+	d, path := Diameter(adj)
+	if d%2 == 0 {
+		return path[d/2 : d/2+1]
+	}
+	return path[d/2 : d/2+2]
+}
+
+// Reroot computes a tree dp for every choice of the root, in O(n) merges.
+//
+// The dp value of the subtree at v, with parent p, is add(acc, v, p), where
+// acc merges the dp values of the children of v, starting from e; merge shall
+// be associative and commutative. The result for root r is add(acc, r, -1),
+// where acc merges the dp values of all the neighbors of r. Each tree of a
+// forest is solved separately.
+func Reroot(adj [][]int, e int64, merge func(a, b int64) int64, add func(acc int64, v, p int) int64) []int64 {
+	n := len(adj)
+	res := make([]int64, n)
+	down := make([]int64, n) // down[u] merges the dp values of the children of u.
+	up := make([]int64, n)   // up[u] is the dp value of the parent side of u.
+	ord, pnt := forest(adj)
+	for _, u := range ord {
+		down[u] = e
+	}
+	for i := len(ord) - 1; i >= 0; i-- {
+		if u, p := ord[i], pnt[ord[i]]; p != -1 {
+			down[p] = merge(down[p], add(down[u], u, p))
+		}
+	}
+	var pre []int64
+	for _, u := range ord {
+		// pre[i] merges the dp values of the neighbors of u before the i-th
+		// one, and suf those after it.
+		pre = append(pre[:0], e)
+		for _, v := range adj[u] {
+			x := up[u]
+			if v != pnt[u] {
+				x = add(down[v], v, u)
+			}
+			pre = append(pre, merge(pre[len(pre)-1], x))
+		}
+		res[u] = add(pre[len(pre)-1], u, -1)
+		suf := e
+		for i := len(adj[u]) - 1; i >= 0; i-- {
+			v := adj[u][i]
+			if v == pnt[u] {
+				suf = merge(suf, up[u])
+			} else {
+				up[v] = add(merge(pre[i], suf), u, v)
+				suf = merge(suf, add(down[v], v, u))
+			}
+		}
+	}
+	return res
+}
+
+// A Hasher assigns canonical ids to trees: two trees hashed by the same Hasher
+// get the same id iff they are isomorphic. Ids of rooted trees and of unrooted
+// trees are never equal.
+type Hasher struct {
+	ids map[string]int
+}
+
+// NewHasher returns a Hasher with no trees seen.
+func NewHasher() *Hasher {
+	return &Hasher{ids: make(map[string]int)}
+}
+
+func (h *Hasher) id(key []byte) int {
+	id, ok := h.ids[string(key)]
+	if !ok {
+		id = len(h.ids)
+		h.ids[string(key)] = id
+	}
+	return id
+}
+
+// Rooted returns the id of the tree containing r, rooted at r.
+func (h *Hasher) Rooted(adj [][]int, r int) int {
+	return h.rooted(adj, r, -1)
+}
+
+// rooted is Rooted, with the node cut removed.
+func (h *Hasher) rooted(adj [][]int, r, cut int) int {
+	ord, pnt, _ := bfs(adj, r, cut)
+	ids := make(map[int]int, len(ord))
+	var cs []int
+	var key []byte
+	for i := len(ord) - 1; i >= 0; i-- {
+		u := ord[i]
+		cs = cs[:0]
+		for _, v := range adj[u] {
+			if v != pnt[u] && v != cut {
+				cs = append(cs, ids[v])
+			}
+		}
+		sort.Ints(cs)
+		key = append(key[:0], 'r')
+		for _, c := range cs {
+			key = strconv.AppendInt(key, int64(c), 10)
+			key = append(key, ',')
+		}
+		ids[u] = h.id(key)
+	}
+	return ids[r]
+}
+
+// Unrooted returns the id of the tree containing node 0, rooted at its center,
+// or at its central edge if it has two centers.
+func (h *Hasher) Unrooted(adj [][]int) int {
+	if len(adj) == 0 {
+		return h.id([]byte("u"))
+	}
+	cs := Centers(adj)
+	key := []byte{'u'}
+	if len(cs) == 1 {
+		key = strconv.AppendInt(key, int64(h.Rooted(adj, cs[0])), 10)
+	} else {
+		x, y := h.rooted(adj, cs[0], cs[1]), h.rooted(adj, cs[1], cs[0])
+		if x > y {
+			x, y = y, x
+		}
+		key = strconv.AppendInt(key, int64(x), 10)
+		key = append(key, ',')
+		key = strconv.AppendInt(key, int64(y), 10)
+	}
+	return h.id(key)
+}
@@ -0,0 +1,184 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomTree(n int) [][]int {
+	adj := make([][]int, n)
+	p := rand.Perm(n)
+	for i := 1; i < n; i++ {
+		u, v := p[i], p[rand.Intn(i)]
+		adj[u] = append(adj[u], v)
+		adj[v] = append(adj[v], u)
+	}
+	return adj
+}
+
+func eccentricities(adj [][]int) [][]int {
+	ds := make([][]int, len(adj))
+	for u := range adj {
+		_, _, ds[u] = bfs(adj, u, -1)
+	}
+	return ds
+}
+
+func TestDiameterAndCenters(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		adj := randomTree(1 + rand.Intn(50))
+		ds := eccentricities(adj)
+		e := 0
+		ecc := make([]int, len(adj))
+		r := len(adj)
+		for u := range ds {
+			for _, d := range ds[u] {
+				if d > ecc[u] {
+					ecc[u] = d
+				}
+			}
+			if ecc[u] > e {
+				e = ecc[u]
+			}
+			if ecc[u] < r {
+				r = ecc[u]
+			}
+		}
+		d, path := Diameter(adj)
+		if d != e || len(path) != d+1 || ds[path[0]][path[d]] != d {
+			t.Errorf("Diameter: expected %d, got %d, %v.", e, d, path)
+		}
+		cs := Centers(adj)
+		var es []int
+		for u := range ecc {
+			if ecc[u] == r {
+				es = append(es, u)
+			}
+		}
+		if len(cs) != len(es) {
+			t.Errorf("Centers: expected %v, got %v.", es, cs)
+		}
+		for _, c := range cs {
+			if ecc[c] != r {
+				t.Errorf("Centers: expected %v, got %v.", es, cs)
+			}
+		}
+	}
+}
+
+func TestReroot(t *testing.T) {
+	n := 300
+	adj := randomTree(n)
+	ord, pnt := forest(adj)
+	sz := make([]int64, n)
+	for i := n - 1; i >= 0; i-- {
+		u := ord[i]
+		sz[u]++
+		if p := pnt[u]; p != -1 {
+			sz[p] += sz[u]
+		}
+	}
+	// Sum of the distances to all the other nodes: each node of the subtree
+	// at v is one further from p than from v.
+	res := Reroot(adj, 0,
+		func(a, b int64) int64 { return a + b },
+		func(acc int64, v, p int) int64 {
+			switch {
+			case p == -1:
+				return acc
+			case pnt[v] == p:
+				return acc + sz[v]
+			default:
+				return acc + int64(n) - sz[p]
+			}
+		})
+	ds := eccentricities(adj)
+	for u := range adj {
+		e := int64(0)
+		for _, d := range ds[u] {
+			e += int64(d)
+		}
+		if res[u] != e {
+			t.Errorf("Reroot: sum of distances from %d: expected %d, got %d.", u, e, res[u])
+		}
+	}
+}
+
+func TestRerootPath(t *testing.T) {
+	const n = 1 << 20
+	adj := make([][]int, n)
+	for u := 1; u < n; u++ {
+		adj[u] = append(adj[u], u-1)
+		adj[u-1] = append(adj[u-1], u)
+	}
+	// The eccentricity of each node.
+	res := Reroot(adj, -1,
+		func(a, b int64) int64 {
+			if a > b {
+				return a
+			}
+			return b
+		},
+		func(acc int64, v, p int) int64 { return acc + 1 })
+	for _, u := range []int{0, 1, n / 2, n - 1} {
+		e := int64(u)
+		if n-1-u > u {
+			e = int64(n - 1 - u)
+		}
+		if res[u] != e {
+			t.Errorf("Reroot: eccentricity of %d: expected %d, got %d.", u, e, res[u])
+		}
+	}
+	if d, _ := Diameter(adj); d != n-1 {
+		t.Errorf("Diameter: expected %d, got %d.", n-1, d)
+	}
+}
+
+func relabel(adj [][]int) ([][]int, []int) {
+	p := rand.Perm(len(adj))
+	b := make([][]int, len(adj))
+	for u := range adj {
+		for _, v := range adj[u] {
+			b[p[u]] = append(b[p[u]], p[v])
+		}
+	}
+	for u := range b {
+		rand.Shuffle(len(b[u]), func(i, j int) { b[u][i], b[u][j] = b[u][j], b[u][i] })
+	}
+	return b, p
+}
+
+func TestHasher(t *testing.T) {
+	h := NewHasher()
+	for i := 0; i < 100; i++ {
+		adj := randomTree(1 + rand.Intn(30))
+		b, p := relabel(adj)
+		if x, y := h.Unrooted(adj), h.Unrooted(b); x != y {
+			t.Errorf("Unrooted: isomorphic trees got %d and %d.", x, y)
+		}
+		r := rand.Intn(len(adj))
+		if x, y := h.Rooted(adj, r), h.Rooted(b, p[r]); x != y {
+			t.Errorf("Rooted: isomorphic trees got %d and %d.", x, y)
+		}
+	}
+
+	// There are 6 unrooted trees and 20 rooted trees of 6 nodes.
+	us := make(map[int]bool)
+	rs := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		adj := randomTree(6)
+		us[h.Unrooted(adj)] = true
+		rs[h.Rooted(adj, 0)] = true
+	}
+	if len(us) != 6 {
+		t.Errorf("Unrooted: expected 6 trees, got %d.", len(us))
+	}
+	if len(rs) != 20 {
+		t.Errorf("Rooted: expected 20 trees, got %d.", len(rs))
+	}
+	for u := range us {
+		if rs[u] {
+			t.Errorf("Rooted and unrooted trees got the same id %d.", u)
+		}
+	}
+}